@@ -0,0 +1,94 @@
+// Command fakestream serves a fake Upfluence stream of generated posts, to develop and load test offline.
+//
+// Usage:
+//
+//	go run ./cmd/fakestream -addr :8081 -rate 2000 -missing-rate 0.1 -malformed-rate 0.01 -disconnect-after 50000
+//
+// The generator can also be configured by a JSON file, see synthetic.ServerConfig, the flags taking precedence.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/synthetic"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "listening address")
+	configPath := flag.String("config", "", "optional JSON configuration of the generator")
+	rate := flag.Float64("rate", 10, "events per second on each connection")
+	platforms := flag.String("platforms", "", "comma-separated platforms of the posts, all if empty")
+	missingRate := flag.Float64("missing-rate", 0, "probability of a metric to be missing from a post")
+	malformedRate := flag.Float64("malformed-rate", 0, "probability of an event not to be a valid post")
+	disconnectAfter := flag.Int("disconnect-after", 0, "events after which connections are closed, 0 to keep them open")
+	seed := flag.Uint64("seed", 0, "seed of the generator, random if 0")
+	flag.Parse()
+
+	log, err := logs.NewLogger(logs.Config{Level: "INFO"})
+	if err != nil {
+		panic(err)
+	}
+
+	config := synthetic.ServerConfig{}
+	if *configPath != "" {
+		content, err := os.ReadFile(*configPath)
+		if err != nil {
+			panic(err)
+		}
+
+		if err := json.Unmarshal(content, &config); err != nil {
+			panic(err)
+		}
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "rate":
+			config.RatePerSecond = *rate
+		case "platforms":
+			config.Platforms = strings.Split(*platforms, ",")
+		case "missing-rate":
+			config.MissingRate = *missingRate
+		case "malformed-rate":
+			config.MalformedRate = *malformedRate
+		case "disconnect-after":
+			config.DisconnectAfter = *disconnectAfter
+		case "seed":
+			config.Seed = *seed
+		}
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/stream", synthetic.NewHandler(config))
+
+	srv := &http.Server{
+		ReadHeaderTimeout: 5 * time.Second,
+		Addr:              *addr,
+		Handler:           mux,
+	}
+
+	go func() {
+		log.Info("Fake stream listening on " + *addr + "/stream")
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(err.Error())
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	// Streams never end by themselves, they are cut without waiting.
+	if err := srv.Close(); err != nil {
+		log.Error("Fail to properly close the server", logs.Field{Key: "error", Value: err.Error()})
+	}
+}
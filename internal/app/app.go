@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -10,7 +11,8 @@ import (
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/config"
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/features/aggregate"
 	ginhttp "github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/http"
-	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sources"
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
 )
 
@@ -20,45 +22,96 @@ type (
 )
 
 func Launch(config config.Config, log *logs.Logger) (RunCallback, CloseCallback, error) {
-	sseClient := sse.NewSSEClient(config.SSEClientConfig, log)
+	source, err := sources.New(config.Source, config.SSEClientConfig, log)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't create source: %w", err)
+	}
+
+	postBus := posts.NewBus(config.PostBus, source)
 
-	aggregateFeature := aggregate.NewAggregateFeatures(sseClient)
+	registry, err := aggregate.NewRegistry(config.Aggregate.Dimensions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't create dimension registry: %w", err)
+	}
+
+	aggregateFeature := aggregate.NewAggregateFeatures(config.Aggregate, registry, postBus)
 
 	router := ginhttp.NewRouter(config.Router, log)
 
-	analysisHandler := ginhttp.NewAnalysisHandler(config.Router.AnalysisHandlerConfig, aggregateFeature, log)
+	analysisHandler, err := ginhttp.NewAnalysisHandler(config.Router.AnalysisHandlerConfig, aggregateFeature, registry, source, log)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't create analysis handler: %w", err)
+	}
 
 	analysisHandler.RegisterRoutes(router)
 
+	healthHandler := ginhttp.NewHealthHandler(source)
+
+	healthHandler.RegisterRoutes(router)
+
+	metricsHandler := ginhttp.NewMetricsHandler(source, postBus)
+
+	metricsHandler.RegisterRoutes(router)
+
 	addrGin := ":" + strconv.Itoa(config.Router.Port)
 	srv := &http.Server{
-		ReadHeaderTimeout: time.Millisecond,
+		ReadHeaderTimeout: 5 * time.Second,
 		Addr:              addrGin,
 		Handler:           router,
 	}
 
+	streamCtx, stopStream := context.WithCancel(context.Background())
+	streamDone := make(chan struct{})
+	busDone := make(chan struct{})
+
 	shutdown := func() error {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.Router.ShutdownTimeout)*time.Second)
-		defer cancel()
+		timeout := time.Duration(config.Router.ShutdownTimeout) * time.Second
 
-		if err := srv.Shutdown(ctx); err != nil {
-			return fmt.Errorf("can't shutdown server: %w", err)
+		serverCtx, cancelServer := context.WithTimeout(context.Background(), timeout)
+		defer cancelServer()
+
+		serverErr := srv.Shutdown(serverCtx)
+
+		// Cancelling the stream context aborts the upstream request and closes every subscriber.
+		stopStream()
+		source.Close()
+
+		// The source has its own deadline, the server may have used up its whole timeout.
+		sourceCtx, cancelSource := context.WithTimeout(context.Background(), timeout)
+		defer cancelSource()
+
+		for _, done := range []chan struct{}{streamDone, busDone} {
+			select {
+			case <-done:
+			case <-sourceCtx.Done():
+				return fmt.Errorf("can't stop source: %w", sourceCtx.Err())
+			}
 		}
 
-		sseClient.Close()
+		if serverErr != nil {
+			return fmt.Errorf("can't shutdown server: %w", serverErr)
+		}
 
 		return nil
 	}
 
 	run := func() {
 		go func() {
-			if err := sseClient.Listen(); err != nil {
-				log.Error("SSE Client error", logs.Field{Key: "error", Value: err.Error()})
+			defer close(streamDone)
+			sources.Run(streamCtx, source, sources.RestartDelay(config.Source, config.SSEClientConfig), log)
+		}()
+
+		go func() {
+			defer close(busDone)
+			if err := postBus.Run(streamCtx); err != nil {
+				log.Error("Post bus stopped", logs.Field{Key: "error", Value: err.Error()})
 			}
 		}()
 
 		log.Info("REST API listening on " + addrGin)
-		log.Error(router.Run(addrGin).Error())
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(err.Error())
+		}
 	}
 
 	return run, shutdown, nil
@@ -0,0 +1,130 @@
+// Package broadcast fans values out to buffered subscribers, applying a slow-consumer policy
+// to the ones which can't keep up.
+package broadcast
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrHubClosed = errors.New("hub closed")
+
+// Hub holds the active subscribers, keyed by ID, and publishes values to them.
+type Hub[T any] struct {
+	mu          sync.RWMutex
+	subscribers map[string]*Subscriber[T]
+	closed      bool
+}
+
+func NewHub[T any]() *Hub[T] {
+	return &Hub[T]{
+		subscribers: make(map[string]*Subscriber[T]),
+	}
+}
+
+// Subscribe creates a subscriber buffering values as set by config. When match is not nil,
+// only the values it accepts are delivered.
+// The subscriber is removed and its channel closed when ctx is done, or when the hub is closed.
+// ErrHubClosed is returned if the hub has already been closed.
+func (h *Hub[T]) Subscribe(ctx context.Context, config Config, match func(T) bool) (*Subscriber[T], error) {
+	return h.SubscribeFrom(ctx, config, match, nil)
+}
+
+// SubscribeFrom is like Subscribe, but the subscriber channel starts with the backlog values,
+// delivered before the published ones. The channel capacity is extended to hold the whole backlog.
+func (h *Hub[T]) SubscribeFrom(ctx context.Context, config Config, match func(T) bool, backlog []T) (*Subscriber[T], error) {
+	id, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber := newSubscriber(id, config, match, len(backlog))
+	for _, value := range backlog {
+		subscriber.channel <- value
+	}
+
+	if err := h.add(subscriber); err != nil {
+		return nil, err
+	}
+
+	context.AfterFunc(ctx, subscriber.Unsubscribe)
+
+	return subscriber, nil
+}
+
+// Publish delivers the value to every subscriber accepting it, and disconnects the ones
+// whose policy requires it.
+func (h *Hub[T]) Publish(value T) {
+	h.mu.RLock()
+	subscribers := make([]*Subscriber[T], 0, len(h.subscribers))
+	for _, sub := range h.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range subscribers {
+		if !sub.accepts(value) {
+			continue
+		}
+
+		if !sub.deliver(value) {
+			sub.Unsubscribe()
+		}
+	}
+}
+
+// Len returns the number of active subscribers.
+func (h *Hub[T]) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.subscribers)
+}
+
+// Close closes every subscriber and rejects new ones. It is safe to call Close several times.
+func (h *Hub[T]) Close() {
+	h.mu.Lock()
+	subscribers := h.subscribers
+	h.subscribers = make(map[string]*Subscriber[T])
+	h.closed = true
+	h.mu.Unlock()
+
+	for _, sub := range subscribers {
+		sub.close()
+	}
+}
+
+// add registers the subscriber. It returns ErrHubClosed if the hub has been closed.
+func (h *Hub[T]) add(sub *Subscriber[T]) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return ErrHubClosed
+	}
+
+	sub.hub = h
+	h.subscribers[sub.id] = sub
+
+	return nil
+}
+
+func (h *Hub[T]) remove(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers, id)
+}
+
+func randomID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("can't generate random id")
+	}
+
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,290 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func collect[T any](sub *Subscriber[T]) <-chan []T {
+	result := make(chan []T, 1)
+
+	go func() {
+		received := []T{}
+		for value := range sub.Events() {
+			received = append(received, value)
+		}
+		result <- received
+	}()
+
+	return result
+}
+
+func TestHubSubscribe(t *testing.T) {
+	hub := NewHub[int]()
+
+	sub, err := hub.Subscribe(context.Background(), Config{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sub.ID() == "" {
+		t.Errorf("subscriber should have an ID")
+	}
+
+	if hub.Len() != 1 {
+		t.Fatalf("expected 1 subscriber, got %d", hub.Len())
+	}
+
+	if sub.hub != hub {
+		t.Errorf("subscriber should be bound to its hub")
+	}
+
+	hub.Close()
+
+	if _, err := hub.Subscribe(context.Background(), Config{}, nil); !errors.Is(err, ErrHubClosed) {
+		t.Errorf("expected error %v, got %v", ErrHubClosed, err)
+	}
+}
+
+func TestHubSubscribeContextDone(t *testing.T) {
+	hub := NewHub[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub, err := hub.Subscribe(ctx, Config{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-sub.Events():
+		if ok {
+			t.Fatalf("expected subscriber channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("subscriber channel should be closed when its context is done")
+	}
+
+	if hub.Len() != 0 {
+		t.Errorf("expected 0 subscriber, got %d", hub.Len())
+	}
+}
+
+func TestHubClose(t *testing.T) {
+	hub := NewHub[int]()
+
+	subscribers := []*Subscriber[int]{}
+	for i := 0; i < 2; i++ {
+		sub, err := hub.Subscribe(context.Background(), Config{}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		subscribers = append(subscribers, sub)
+	}
+
+	subscribers[0].Unsubscribe()
+	hub.Close()
+	hub.Close()
+
+	for _, sub := range subscribers {
+		if _, ok := <-sub.Events(); ok {
+			t.Errorf("expected subscriber %s channel to be closed", sub.ID())
+		}
+	}
+
+	if hub.Len() != 0 {
+		t.Errorf("expected 0 subscriber, got %d", hub.Len())
+	}
+}
+
+func TestHubPublish(t *testing.T) {
+	hub := NewHub[int]()
+
+	slow, err := hub.Subscribe(context.Background(), Config{BufferSize: 1}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	disconnected, err := hub.Subscribe(context.Background(), Config{BufferSize: 1, Policy: Disconnect}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hub.Publish(1)
+	hub.Publish(2)
+
+	// A slow subscriber keeps its subscription and reports its loss.
+	if slow.Dropped() != 1 {
+		t.Errorf("expected 1 dropped value, got %d", slow.Dropped())
+	}
+
+	if hub.Len() != 1 || hub.subscribers[slow.ID()] != slow {
+		t.Fatalf("expected only the slow subscriber to remain")
+	}
+
+	// A disconnected subscriber receives the buffered values then its channel is closed.
+	if value := <-disconnected.Events(); value != 1 {
+		t.Errorf("expected value 1, got %d", value)
+	}
+
+	if _, ok := <-disconnected.Events(); ok {
+		t.Errorf("expected disconnected subscriber channel to be closed")
+	}
+}
+
+func TestHubPublishMatch(t *testing.T) {
+	hub := NewHub[int]()
+
+	even, err := hub.Subscribe(context.Background(), Config{}, func(value int) bool {
+		return value%2 == 0
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	all, err := hub.Subscribe(context.Background(), Config{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		hub.Publish(i)
+	}
+	hub.Close()
+
+	if count := len(<-collect(even)); count != 2 {
+		t.Errorf("expected 2 values for filtered subscriber, got %d", count)
+	}
+
+	if count := len(<-collect(all)); count != 4 {
+		t.Errorf("expected 4 values for unfiltered subscriber, got %d", count)
+	}
+
+	if even.Dropped() != 0 {
+		t.Errorf("filtered values shouldn't be counted as dropped, got %d", even.Dropped())
+	}
+}
+
+func TestHubSubscribeFrom(t *testing.T) {
+	hub := NewHub[int]()
+
+	sub, err := hub.SubscribeFrom(context.Background(), Config{BufferSize: 1}, nil, []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hub.Publish(4)
+	hub.Close()
+
+	values := <-collect(sub)
+	if !slices.Equal(values, []int{1, 2, 3, 4}) {
+		t.Errorf("expected backlog then published values, got %v", values)
+	}
+
+	if sub.Dropped() != 0 {
+		t.Errorf("expected no dropped value, got %d", sub.Dropped())
+	}
+}
+
+func TestHubUnsubscribeUnblocksDelivery(t *testing.T) {
+	hub := NewHub[int]()
+
+	sub, err := hub.Subscribe(context.Background(), Config{
+		BufferSize:     1,
+		Policy:         BlockWithTimeout,
+		BlockTimeoutMs: 10000,
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hub.Publish(1)
+
+	publishDone := make(chan struct{})
+	go func() {
+		hub.Publish(2)
+		close(publishDone)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	sub.Unsubscribe()
+
+	select {
+	case <-publishDone:
+	case <-time.After(time.Second):
+		t.Fatal("unsubscribing should interrupt a blocked delivery")
+	}
+}
+
+// TestHubConcurrentAccess must be run with the race detector.
+func TestHubConcurrentAccess(t *testing.T) {
+	policies := []SlowConsumerPolicy{DropNewest, DropOldest, BlockWithTimeout, Disconnect}
+
+	hub := NewHub[int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+
+	// Publisher
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ctx.Err() == nil; i++ {
+			hub.Publish(i)
+		}
+	}()
+
+	// Subscribers with various lifetimes and consumption speeds
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for ctx.Err() == nil {
+				subCtx, subCancel := context.WithTimeout(ctx, time.Duration(i)*time.Millisecond)
+
+				sub, err := hub.Subscribe(subCtx, Config{
+					BufferSize:     i % 4,
+					Policy:         policies[i%len(policies)],
+					BlockTimeoutMs: 1,
+				}, nil)
+				if err != nil {
+					subCancel()
+					if !errors.Is(err, ErrHubClosed) {
+						t.Errorf("unexpected error: %v", err)
+					}
+					return
+				}
+
+				for received := 0; received < i; received++ {
+					if _, ok := <-sub.Events(); !ok {
+						break
+					}
+				}
+
+				if i%2 == 0 {
+					sub.Unsubscribe()
+				}
+				subCancel()
+			}
+		}(i)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	hub.Close()
+	cancel()
+
+	wg.Wait()
+
+	if hub.Len() != 0 {
+		t.Errorf("expected 0 subscriber after close, got %d", hub.Len())
+	}
+}
@@ -0,0 +1,172 @@
+package broadcast
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultBufferSize   = 64
+	defaultBlockTimeout = 100 * time.Millisecond
+)
+
+// SlowConsumerPolicy defines what happens to a value when a subscriber buffer is full.
+type SlowConsumerPolicy string
+
+const (
+	// DropNewest discards the value being published. It is the default policy.
+	DropNewest SlowConsumerPolicy = "drop_newest"
+
+	// DropOldest discards the oldest buffered value to make room for the new one.
+	DropOldest SlowConsumerPolicy = "drop_oldest"
+
+	// BlockWithTimeout waits for the subscriber to make room, and drops the value after the timeout.
+	BlockWithTimeout SlowConsumerPolicy = "block"
+
+	// Disconnect removes the subscriber and closes its channel.
+	Disconnect SlowConsumerPolicy = "disconnect"
+)
+
+// Config configures the buffering of a subscriber.
+type Config struct {
+	// BufferSize is the capacity of the subscriber channel, defaults to 64.
+	BufferSize int `json:"buffer_size"`
+
+	// Policy applied when the buffer is full, defaults to DropNewest.
+	Policy SlowConsumerPolicy `json:"slow_consumer_policy"`
+
+	// BlockTimeoutMs is how long BlockWithTimeout waits, in milliseconds. Defaults to 100ms.
+	BlockTimeoutMs int `json:"block_timeout_ms"`
+}
+
+// Subscriber is a handle on a subscription to a hub.
+// It is owned by the code that created it, which must call Unsubscribe once done.
+type Subscriber[T any] struct {
+	id      string
+	channel chan T
+	hub     *Hub[T]
+
+	match        func(T) bool
+	policy       SlowConsumerPolicy
+	blockTimeout time.Duration
+	dropped      atomic.Uint64
+
+	// mu serializes deliveries and the channel closing, done interrupts a blocked delivery.
+	mu        sync.Mutex
+	closed    bool
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newSubscriber[T any](id string, config Config, match func(T) bool, backlogSize int) *Subscriber[T] {
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	blockTimeout := time.Duration(config.BlockTimeoutMs) * time.Millisecond
+	if blockTimeout <= 0 {
+		blockTimeout = defaultBlockTimeout
+	}
+
+	return &Subscriber[T]{
+		id:           id,
+		channel:      make(chan T, bufferSize+backlogSize),
+		match:        match,
+		policy:       config.Policy,
+		blockTimeout: blockTimeout,
+		done:         make(chan struct{}),
+	}
+}
+
+func (s *Subscriber[T]) ID() string {
+	return s.id
+}
+
+// Events returns the channel delivering the values. It is closed once the subscriber
+// is unsubscribed, disconnected by its policy, or when the hub is closed.
+func (s *Subscriber[T]) Events() <-chan T {
+	return s.channel
+}
+
+// Dropped returns the number of values the subscriber lost because it was too slow.
+func (s *Subscriber[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe removes the subscriber from the hub and closes its channel.
+// It is safe to call Unsubscribe several times, and after the hub has been closed.
+func (s *Subscriber[T]) Unsubscribe() {
+	if s.hub != nil {
+		s.hub.remove(s.id)
+	}
+
+	s.close()
+}
+
+func (s *Subscriber[T]) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.closed = true
+		close(s.channel)
+	})
+}
+
+// accepts reports whether the subscriber wants the value.
+func (s *Subscriber[T]) accepts(value T) bool {
+	return s.match == nil || s.match(value)
+}
+
+// deliver sends the value to the subscriber, applying its policy if the buffer is full.
+// It returns false if the subscriber must be disconnected.
+func (s *Subscriber[T]) deliver(value T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return true
+	}
+
+	select {
+	case s.channel <- value:
+		return true
+	default:
+	}
+
+	switch s.policy {
+	case DropOldest:
+		select {
+		case <-s.channel:
+			s.dropped.Add(1)
+		default:
+		}
+
+		select {
+		case s.channel <- value:
+		default:
+			s.dropped.Add(1)
+		}
+	case BlockWithTimeout:
+		timer := time.NewTimer(s.blockTimeout)
+		defer timer.Stop()
+
+		select {
+		case s.channel <- value:
+		case <-timer.C:
+			s.dropped.Add(1)
+		case <-s.done:
+		}
+	case Disconnect:
+		s.dropped.Add(1)
+		return false
+	default:
+		s.dropped.Add(1)
+	}
+
+	return true
+}
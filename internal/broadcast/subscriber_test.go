@@ -0,0 +1,139 @@
+package broadcast
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSubscriber(t *testing.T) {
+	type testData struct {
+		name                 string
+		config               Config
+		expectedBufferSize   int
+		expectedBlockTimeout time.Duration
+	}
+
+	testCases := [...]testData{
+		{
+			name:                 "Success case: default values",
+			config:               Config{},
+			expectedBufferSize:   defaultBufferSize,
+			expectedBlockTimeout: defaultBlockTimeout,
+		},
+		{
+			name: "Success case: custom values",
+			config: Config{
+				BufferSize:     3,
+				Policy:         BlockWithTimeout,
+				BlockTimeoutMs: 10,
+			},
+			expectedBufferSize:   3,
+			expectedBlockTimeout: 10 * time.Millisecond,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sub := newSubscriber[string]("id", testCase.config, nil, 0)
+
+			if cap(sub.channel) != testCase.expectedBufferSize {
+				t.Errorf("expected buffer size %d, got %d", testCase.expectedBufferSize, cap(sub.channel))
+			}
+
+			if sub.blockTimeout != testCase.expectedBlockTimeout {
+				t.Errorf("expected block timeout %v, got %v", testCase.expectedBlockTimeout, sub.blockTimeout)
+			}
+		})
+	}
+}
+
+func TestSubscriberDeliver(t *testing.T) {
+	type testData struct {
+		name              string
+		policy            SlowConsumerPolicy
+		expectedConnected bool
+		expectedDropped   uint64
+		expectedBuffered  []string
+	}
+
+	testCases := [...]testData{
+		{
+			name:              "Success case: drop newest",
+			policy:            DropNewest,
+			expectedConnected: true,
+			expectedDropped:   1,
+			expectedBuffered:  []string{"1", "2"},
+		},
+		{
+			name:              "Success case: drop oldest",
+			policy:            DropOldest,
+			expectedConnected: true,
+			expectedDropped:   1,
+			expectedBuffered:  []string{"2", "3"},
+		},
+		{
+			name:              "Success case: block with timeout",
+			policy:            BlockWithTimeout,
+			expectedConnected: true,
+			expectedDropped:   1,
+			expectedBuffered:  []string{"1", "2"},
+		},
+		{
+			name:              "Success case: disconnect",
+			policy:            Disconnect,
+			expectedConnected: false,
+			expectedDropped:   1,
+			expectedBuffered:  []string{"1", "2"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			sub := newSubscriber[string]("id", Config{
+				BufferSize:     2,
+				Policy:         testCase.policy,
+				BlockTimeoutMs: 10,
+			}, nil, 0)
+
+			connected := true
+			for _, data := range []string{"1", "2", "3"} {
+				connected = sub.deliver(data)
+			}
+
+			if connected != testCase.expectedConnected {
+				t.Errorf("expected connected to be %v, got %v", testCase.expectedConnected, connected)
+			}
+
+			if sub.Dropped() != testCase.expectedDropped {
+				t.Errorf("expected %d dropped values, got %d", testCase.expectedDropped, sub.Dropped())
+			}
+
+			for _, expected := range testCase.expectedBuffered {
+				if value := <-sub.channel; value != expected {
+					t.Errorf("expected buffered value %s, got %s", expected, value)
+				}
+			}
+		})
+	}
+}
+
+func TestSubscriberDeliverBlockWithTimeoutWaitsForConsumer(t *testing.T) {
+	sub := newSubscriber[string]("id", Config{
+		BufferSize:     1,
+		Policy:         BlockWithTimeout,
+		BlockTimeoutMs: 1000,
+	}, nil, 0)
+
+	sub.deliver("1")
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		<-sub.channel
+	}()
+
+	sub.deliver("2")
+
+	if sub.Dropped() != 0 {
+		t.Errorf("expected no dropped value, got %d", sub.Dropped())
+	}
+}
@@ -5,15 +5,21 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/features/aggregate"
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/http"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sources"
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
 )
 
 type Config struct {
-	SSEClientConfig sse.Config  `json:"sse_client_config"`
-	Router          http.Config `json:"router"`
-	Logger          logs.Config `json:"logger"`
+	Source          sources.Config   `json:"source"`
+	SSEClientConfig sse.Configs      `json:"sse_client_config"`
+	PostBus         posts.Config     `json:"post_bus"`
+	Aggregate       aggregate.Config `json:"aggregate"`
+	Router          http.Config      `json:"router"`
+	Logger          logs.Config      `json:"logger"`
 }
 
 func Load(path string) (*Config, error) {
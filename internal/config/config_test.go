@@ -4,9 +4,12 @@ import (
 	"os"
 	"slices"
 	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sources"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
 )
 
-var rawConfig = `{"sse_client_config":{"server_url":"https://stream.upfluence.co/stream","max_reconnection_attempts":10},"router":{"port":8080,"gin_mode":"debug","shutdown_timeout":5,"analysis_handler_config":{"authorized_dimensions":["likes","comments","favorites","retweets"]}},"logger":{"level":"INFO"}}`
+var rawConfig = `{"source":{"type":"synthetic","synthetic":{"rate_per_second":500,"platforms":["tweet"]}},"sse_client_config":{"server_url":"https://stream.upfluence.co/stream","max_reconnection_attempts":10,"reconnection_policy":{"initial_delay_ms":50,"unlimited":true}},"post_bus":{"upstream":{"buffer_size":4096,"slow_consumer_policy":"block"}},"aggregate":{"strict_decoding":true,"dimensions":[{"name":"likes"},{"name":"plays","field":"views","platforms":["tiktok_video"]},{"name":"like_rate","expression":"likes / plays","unit":"ratio"}],"subscriber":{"buffer_size":128,"slow_consumer_policy":"drop_oldest"}},"router":{"port":8080,"gin_mode":"debug","shutdown_timeout":5,"analysis_handler_config":{"authorized_dimensions":["likes","comments","favorites","retweets"]}},"logger":{"level":"INFO"}}`
 
 func TestLoad(t *testing.T) {
 	dir := t.TempDir()
@@ -25,12 +28,70 @@ func TestLoad(t *testing.T) {
 		t.Errorf("expected Logger.Level to be 'INFO', got '%s'", config.Logger.Level)
 	}
 
-	if config.SSEClientConfig.ServerURL != "https://stream.upfluence.co/stream" {
-		t.Errorf("expected SSEClientConfig.ServerURL to be 'https://stream.upfluence.co/stream', got '%s'", config.SSEClientConfig.ServerURL)
+	if config.Source.Type != sources.TypeSynthetic {
+		t.Errorf("expected Source.Type to be '%s', got '%s'", sources.TypeSynthetic, config.Source.Type)
+	}
+
+	if config.Source.Synthetic.RatePerSecond != 500 {
+		t.Errorf("expected Source.Synthetic.RatePerSecond to be 500, got '%v'", config.Source.Synthetic.RatePerSecond)
+	}
+
+	if !slices.Equal(config.Source.Synthetic.Platforms, []string{"tweet"}) {
+		t.Errorf("expected Source.Synthetic.Platforms to be [tweet], got %v", config.Source.Synthetic.Platforms)
+	}
+
+	if len(config.SSEClientConfig) != 1 {
+		t.Fatalf("expected a single SSEClientConfig, got %d", len(config.SSEClientConfig))
+	}
+
+	if config.SSEClientConfig[0].ServerURL != "https://stream.upfluence.co/stream" {
+		t.Errorf("expected SSEClientConfig.ServerURL to be 'https://stream.upfluence.co/stream', got '%s'", config.SSEClientConfig[0].ServerURL)
+	}
+
+	if config.SSEClientConfig[0].MaxReconnectionAttempts != 10 {
+		t.Errorf("expected SSEClientConfig.MaxReconnectionAttempts to be 10, got '%d'", config.SSEClientConfig[0].MaxReconnectionAttempts)
+	}
+
+	if config.SSEClientConfig[0].ReconnectionPolicy.InitialDelayMs != 50 {
+		t.Errorf("expected SSEClientConfig.ReconnectionPolicy.InitialDelayMs to be 50, got '%d'", config.SSEClientConfig[0].ReconnectionPolicy.InitialDelayMs)
 	}
 
-	if config.SSEClientConfig.MaxReconnectionAttempts != 10 {
-		t.Errorf("expected SSEClientConfig.MaxReconnectionAttempts to be 10, got '%d'", config.SSEClientConfig.MaxReconnectionAttempts)
+	if !config.SSEClientConfig[0].ReconnectionPolicy.Unlimited {
+		t.Errorf("expected SSEClientConfig.ReconnectionPolicy.Unlimited to be true")
+	}
+
+	if config.PostBus.Upstream.BufferSize != 4096 {
+		t.Errorf("expected PostBus.Upstream.BufferSize to be 4096, got '%d'", config.PostBus.Upstream.BufferSize)
+	}
+
+	if config.PostBus.Upstream.Policy != sse.BlockWithTimeout {
+		t.Errorf("expected PostBus.Upstream.Policy to be '%s', got '%s'", sse.BlockWithTimeout, config.PostBus.Upstream.Policy)
+	}
+
+	if config.Aggregate.Subscriber.BufferSize != 128 {
+		t.Errorf("expected Aggregate.Subscriber.BufferSize to be 128, got '%d'", config.Aggregate.Subscriber.BufferSize)
+	}
+
+	if config.Aggregate.Subscriber.Policy != sse.DropOldest {
+		t.Errorf("expected Aggregate.Subscriber.Policy to be '%s', got '%s'", sse.DropOldest, config.Aggregate.Subscriber.Policy)
+	}
+
+	if !config.Aggregate.StrictDecoding {
+		t.Errorf("expected Aggregate.StrictDecoding to be true")
+	}
+
+	if len(config.Aggregate.Dimensions) != 3 {
+		t.Fatalf("expected 3 Aggregate.Dimensions, got %d", len(config.Aggregate.Dimensions))
+	}
+
+	plays := config.Aggregate.Dimensions[1]
+	if plays.Name != "plays" || plays.Field != "views" || !slices.Equal(plays.Platforms, []string{"tiktok_video"}) {
+		t.Errorf("expected Aggregate.Dimensions[1] to be plays from the views of tiktok_video, got %+v", plays)
+	}
+
+	likeRate := config.Aggregate.Dimensions[2]
+	if likeRate.Name != "like_rate" || likeRate.Expression != "likes / plays" || likeRate.Unit != "ratio" {
+		t.Errorf("expected Aggregate.Dimensions[2] to be the like_rate ratio derived from likes and plays, got %+v", likeRate)
 	}
 
 	if config.Router.Port != 8080 {
@@ -57,6 +118,40 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestLoadStreams(t *testing.T) {
+	dir := t.TempDir()
+	configPath := dir + "/config.json"
+	err := os.WriteFile(configPath, []byte(`{"sse_client_config":[{"name":"eu","server_url":"https://eu.example.com/stream","transport":{"headers":{"X-Region":"eu"},"bearer_token_env":"STREAM_TOKEN","dial_timeout_ms":500}},{"name":"us","server_url":"https://us.example.com/stream"}]}`), 0o600)
+	if err != nil {
+		t.Fatalf("unexpected error while writing config file: %v", err)
+	}
+
+	config, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error while loading config: %v", err)
+	}
+
+	if len(config.SSEClientConfig) != 2 {
+		t.Fatalf("expected 2 SSEClientConfig, got %d", len(config.SSEClientConfig))
+	}
+
+	if config.SSEClientConfig[0].Name != "eu" || config.SSEClientConfig[1].Name != "us" {
+		t.Errorf("expected streams eu and us, got %s and %s", config.SSEClientConfig[0].Name, config.SSEClientConfig[1].Name)
+	}
+
+	if config.SSEClientConfig[0].Transport.Headers["X-Region"] != "eu" {
+		t.Errorf("expected SSEClientConfig[0].Transport.Headers to hold X-Region, got %v", config.SSEClientConfig[0].Transport.Headers)
+	}
+
+	if config.SSEClientConfig[0].Transport.BearerTokenEnv != "STREAM_TOKEN" {
+		t.Errorf("expected SSEClientConfig[0].Transport.BearerTokenEnv to be 'STREAM_TOKEN', got '%s'", config.SSEClientConfig[0].Transport.BearerTokenEnv)
+	}
+
+	if config.SSEClientConfig[0].Transport.DialTimeoutMs != 500 {
+		t.Errorf("expected SSEClientConfig[0].Transport.DialTimeoutMs to be 500, got '%d'", config.SSEClientConfig[0].Transport.DialTimeoutMs)
+	}
+}
+
 func TestLoadInvalidPath(t *testing.T) {
 	_, err := Load("invalid")
 	if err == nil {
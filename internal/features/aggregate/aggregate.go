@@ -1,19 +1,62 @@
 package aggregate
 
 import (
+	"context"
 	"time"
 
-	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
 )
 
+// GroupByPlatform groups the posts by the platform they have been published on.
+const GroupByPlatform = "platform"
+
 type AggregateFeatures interface { //nolint:revive
-	Aggregate(duration time.Duration, dimension string) (*PostsStatAggregation, error)
+	Aggregate(ctx context.Context, query Query) (*PostsStatAggregation, error)
+}
+
+// Query describes the posts to aggregate and how.
+type Query struct {
+	// Duration of the window of live posts to aggregate.
+	Duration time.Duration
+
+	// Lookback extends the window to the posts received during this past duration,
+	// served from the replay buffer of the stream.
+	Lookback time.Duration
+
+	// Dimensions are the names of the post metrics to average, all computed on the same window.
+	Dimensions []string
+
+	// IncludeAbsent counts the posts that don't carry a dimension as 0 in its average and stats,
+	// instead of leaving them out.
+	IncludeAbsent bool
+
+	// Precision rounds the averages and the stats to this number of decimals, if set.
+	Precision *int
+
+	// IntegerAverages truncates the averages to integers, as they used to be.
+	IntegerAverages bool
+
+	// Stats adds the distribution of each dimension to the averages.
+	Stats bool
+
+	// Platforms restricts the posts to the ones of the selected platforms.
+	Platforms PlatformFilter
+
+	// GroupBy breaks the statistics down by the given post attribute, if set. Only GroupByPlatform is supported.
+	GroupBy string
+
+	// Source restricts the posts to the ones received from this stream, all the streams if empty.
+	Source string
 }
 
-func NewAggregateFeatures(sseClient *sse.Client) AggregateFeatures {
+// NewAggregateFeatures creates the aggregate features of the dimensions of registry, for the posts of bus.
+func NewAggregateFeatures(config Config, registry *Registry, bus *posts.Bus) AggregateFeatures {
 	repo := &postStatsRepository{
-		sseClient: sseClient,
+		bus:              bus,
+		subscriberConfig: config.Subscriber,
+		strictDecoding:   config.StrictDecoding,
+		registry:         registry,
 	}
 
-	return newAggregateController(repo)
+	return newAggregateController(repo, registry)
 }
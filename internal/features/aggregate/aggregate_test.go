@@ -3,13 +3,13 @@ package aggregate
 import (
 	"testing"
 
-	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
 )
 
 func TestNewAggregateFeatures(t *testing.T) {
-	sseClient := &sse.Client{}
+	bus := &posts.Bus{}
 
-	feature := NewAggregateFeatures(sseClient)
+	feature := NewAggregateFeatures(Config{}, testRegistry(t), bus)
 
 	if feature == nil {
 		t.Error("aggregate feature factory creates a nil feature")
@@ -0,0 +1,16 @@
+package aggregate
+
+import "github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
+
+type Config struct {
+	// Subscriber configures how each analysis window buffers the stream.
+	Subscriber posts.SubscriberConfig `json:"subscriber"`
+
+	// StrictDecoding fails the analysis on the first event that isn't a valid post, instead of
+	// skipping and counting it. It is meant for debugging.
+	StrictDecoding bool `json:"strict_decoding"`
+
+	// Dimensions declares the numeric post fields that can be analysed, DefaultDimensions if empty.
+	// They make up the dimension registry.
+	Dimensions []Dimension `json:"dimensions"`
+}
@@ -2,122 +2,175 @@ package aggregate
 
 import (
 	"cmp"
+	"context"
 	"errors"
 	"fmt"
+	"math"
 	"slices"
-	"time"
 )
 
 var (
 	_                   AggregateFeatures = (*aggregateController)(nil)
 	ErrNoPostsAvailable                   = errors.New("no posts available")
 	ErrUnknownDimension                   = errors.New("unknown dimension")
+	ErrUnknownGroup                       = errors.New("unknown group")
 )
 
 type aggregateController struct {
 	postStatsRepository iPostStatsRepository
+	registry            *Registry
 }
 
-func newAggregateController(postStatsRepository iPostStatsRepository) *aggregateController {
+func newAggregateController(postStatsRepository iPostStatsRepository, registry *Registry) *aggregateController {
 	return &aggregateController{
 		postStatsRepository: postStatsRepository,
+		registry:            registry,
 	}
 }
 
-func (c *aggregateController) Aggregate(duration time.Duration, dimension string) (*PostsStatAggregation, error) {
-	poststats, err := c.postStatsRepository.ReadFor(duration)
+func (c *aggregateController) Aggregate(ctx context.Context, query Query) (*PostsStatAggregation, error) {
+	if query.GroupBy != "" && query.GroupBy != GroupByPlatform {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGroup, query.GroupBy)
+	}
+
+	window, err := c.postStatsRepository.ReadFor(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("can't read aggregate by id: %w", err)
 	}
 
+	poststats := filterPlatforms(window.Posts, query.Platforms)
+
 	if len(poststats) == 0 {
 		return nil, ErrNoPostsAvailable
 	}
 
-	oldestPost := slices.MinFunc(poststats, func(a, b postStats) int {
+	stats, err := c.computePostsStats(poststats, query)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregation := &PostsStatAggregation{
+		PostsStats:    *stats,
+		DroppedEvents: window.DroppedEvents,
+		DecodeErrors:  window.DecodeErrors,
+		SkippedEvents: window.SkippedEvents,
+	}
+
+	if query.GroupBy == GroupByPlatform {
+		aggregation.Platforms = make(map[string]*PostsStats)
+
+		for platform, platformPosts := range groupByPlatform(poststats) {
+			// The dimensions have been validated with the overall statistics.
+			aggregation.Platforms[platform], _ = c.computePostsStats(platformPosts, query)
+		}
+	}
+
+	return aggregation, nil
+}
+
+// computePostsStats returns the statistics of the query dimensions over postsStats, which must not be empty.
+func (c *aggregateController) computePostsStats(postsStats []postStats, query Query) (*PostsStats, error) {
+	oldestPost := slices.MinFunc(postsStats, func(a, b postStats) int {
 		return cmp.Compare(a.Timestamp, b.Timestamp)
 	})
 
-	latestPost := slices.MaxFunc(poststats, func(a, b postStats) int {
+	latestPost := slices.MaxFunc(postsStats, func(a, b postStats) int {
 		return cmp.Compare(a.Timestamp, b.Timestamp)
 	})
 
-	aggregation := &PostsStatAggregation{
-		TotalPosts:       len(poststats),
+	stats := &PostsStats{
+		TotalPosts:       len(postsStats),
 		MinimumTimestamp: oldestPost.Timestamp,
 		MaximumTimestamp: latestPost.Timestamp,
+		SampleSize:       make(map[string]int, len(query.Dimensions)),
+		Averages:         make(map[string]float64, len(query.Dimensions)),
 	}
 
-	switch dimension {
-	case "likes":
-		aggregation.AvgLikes = intP(c.computeAvgLikes(poststats))
-		return aggregation, nil
-	case "comments":
-		aggregation.AvgComments = intP(c.computeAvgComments(poststats))
-		return aggregation, nil
-	case "favorites":
-		aggregation.AvgFavorites = intP(c.computeAvgFavorites(poststats))
-		return aggregation, nil
-	case "retweets":
-		aggregation.AvgRetweets = intP(c.computeAvgRetweets(poststats))
-		return aggregation, nil
-	default:
-		return nil, ErrUnknownDimension
+	if query.Stats {
+		stats.Stats = make(map[string]DimensionStats, len(query.Dimensions))
 	}
-}
 
-func (c *aggregateController) computeAvgLikes(postsStats []postStats) int {
-	if len(postsStats) == 0 {
-		return 0
+	for _, dimension := range query.Dimensions {
+		declared, ok := c.registry.Lookup(dimension)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownDimension, dimension)
+		}
+
+		values := c.samples(postsStats, dimension, query.IncludeAbsent)
+		stats.Averages[dimension] = c.roundAvg(c.computeAvg(values, declared.Derived()), query)
+
+		stats.SampleSize[dimension] = c.sampleSize(postsStats, dimension)
+
+		if query.Stats {
+			dimensionStats := computeStats(values, declared.Derived())
+			if query.Precision != nil {
+				dimensionStats = dimensionStats.round(*query.Precision)
+			}
+
+			stats.Stats[dimension] = dimensionStats
+		}
 	}
 
-	sum := 0
+	return stats, nil
+}
+
+// groupByPlatform returns postsStats keyed by platform, in their original order.
+func groupByPlatform(postsStats []postStats) map[string][]postStats {
+	groups := make(map[string][]postStats)
+
 	for _, stat := range postsStats {
-		sum += stat.Likes
+		groups[stat.Platform] = append(groups[stat.Platform], stat)
 	}
 
-	return sum / len(postsStats)
+	return groups
 }
 
-func (c *aggregateController) computeAvgComments(postsStats []postStats) int {
-	if len(postsStats) == 0 {
-		return 0
-	}
+// samples returns the values of dimension in postsStats. The posts that don't carry the dimension
+// are left out, unless includeAbsent is set, in which case they count as 0.
+func (c *aggregateController) samples(postsStats []postStats, dimension string, includeAbsent bool) []float64 {
+	values := make([]float64, 0, len(postsStats))
 
-	sum := 0
 	for _, stat := range postsStats {
-		sum += stat.Comments
+		value, ok := stat.value(dimension)
+		if ok || includeAbsent {
+			values = append(values, value)
+		}
 	}
 
-	return sum / len(postsStats)
+	return values
 }
 
-func (c *aggregateController) computeAvgFavorites(postsStats []postStats) int {
-	if len(postsStats) == 0 {
-		return 0
-	}
+// sampleSize returns the number of posts carrying dimension.
+func (c *aggregateController) sampleSize(postsStats []postStats, dimension string) int {
+	size := 0
 
-	sum := 0
 	for _, stat := range postsStats {
-		sum += stat.Favorites
+		if _, ok := stat.value(dimension); ok {
+			size++
+		}
 	}
 
-	return sum / len(postsStats)
+	return size
 }
 
-func (c *aggregateController) computeAvgRetweets(postsStats []postStats) int {
-	if len(postsStats) == 0 {
+// computeAvg returns the average of values, the ones of a derived dimension if derived.
+func (c *aggregateController) computeAvg(values []float64, derived bool) float64 {
+	if len(values) == 0 {
 		return 0
 	}
 
-	sum := 0
-	for _, stat := range postsStats {
-		sum += stat.Retweets
+	return newSum(values, derived).Value() / float64(len(values))
+}
+
+// roundAvg rounds average as requested by query.
+func (c *aggregateController) roundAvg(average float64, query Query) float64 {
+	if query.IntegerAverages {
+		return math.Trunc(average)
 	}
 
-	return sum / len(postsStats)
-}
+	if query.Precision != nil {
+		return round(average, *query.Precision)
+	}
 
-func intP(i int) *int {
-	return &i
+	return average
 }
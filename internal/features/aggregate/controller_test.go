@@ -1,67 +1,104 @@
 package aggregate
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
 	"testing"
 	"time"
 )
 
 type postStatsRepositoryMocking struct {
-	returnError bool
-	NoResults   bool
+	returnError   bool
+	NoResults     bool
+	AbsentMetrics bool
 }
 
-func (r *postStatsRepositoryMocking) ReadFor(_ time.Duration) ([]postStats, error) {
+func (r *postStatsRepositoryMocking) ReadFor(_ context.Context, _ Query) (*postStatsWindow, error) {
 	if r.returnError {
 		return nil, fmt.Errorf("error")
 	}
 
 	if r.NoResults {
-		return nil, nil
+		return &postStatsWindow{}, nil
 	}
 
-	return []postStats{
+	posts := []postStats{
 		{
-			Likes:     1,
-			Comments:  2,
-			Favorites: 3,
-			Retweets:  4,
+			Platform:  "tweet",
 			Timestamp: 5,
+			Metrics:   map[string]float64{"likes": 1, "comments": 2, "favorites": 3, "retweets": 4},
 		},
 		{
-			Likes:     7,
-			Comments:  8,
-			Favorites: 9,
-			Retweets:  10,
+			Platform:  "tweet",
 			Timestamp: 11,
+			Metrics:   map[string]float64{"likes": 7, "comments": 8, "favorites": 9, "retweets": 10},
 		},
+	}
+
+	// A post carrying only likes, such as a youtube video.
+	if r.AbsentMetrics {
+		posts = append(posts, postStats{
+			Platform:  "youtube_video",
+			Timestamp: 8,
+			Metrics:   map[string]float64{"likes": 1},
+		})
+	}
+
+	return &postStatsWindow{
+		Posts:         posts,
+		DroppedEvents: 3,
+		DecodeErrors:  1,
+		SkippedEvents: 2,
 	}, nil
 }
 
 func equalPostsStatAggregation(a, b PostsStatAggregation) bool {
+	if !equalPostsStats(a.PostsStats, b.PostsStats) ||
+		a.DroppedEvents != b.DroppedEvents ||
+		a.DecodeErrors != b.DecodeErrors ||
+		a.SkippedEvents != b.SkippedEvents {
+		return false
+	}
+	if len(a.Platforms) != len(b.Platforms) {
+		return false
+	}
+	for platform, stats := range a.Platforms {
+		if other, ok := b.Platforms[platform]; !ok || !equalPostsStats(*stats, *other) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalPostsStats(a, b PostsStats) bool {
 	if a.TotalPosts != b.TotalPosts ||
 		a.MinimumTimestamp != b.MinimumTimestamp ||
 		a.MaximumTimestamp != b.MaximumTimestamp {
 		return false
 	}
-	if (a.AvgLikes == nil) != (b.AvgLikes == nil) || (a.AvgLikes != nil && *a.AvgLikes != *b.AvgLikes) {
+	if !maps.Equal(a.Averages, b.Averages) {
 		return false
 	}
-	if (a.AvgComments == nil) != (b.AvgComments == nil) || (a.AvgComments != nil && *a.AvgComments != *b.AvgComments) {
+	if !maps.Equal(a.SampleSize, b.SampleSize) {
 		return false
 	}
-	if (a.AvgFavorites == nil) != (b.AvgFavorites == nil) || (a.AvgFavorites != nil && *a.AvgFavorites != *b.AvgFavorites) {
+	if len(a.Stats) != len(b.Stats) {
 		return false
 	}
-	if (a.AvgRetweets == nil) != (b.AvgRetweets == nil) || (a.AvgRetweets != nil && *a.AvgRetweets != *b.AvgRetweets) {
-		return false
+	for dimension, stats := range a.Stats {
+		if other, ok := b.Stats[dimension]; !ok || !equalDimensionStats(stats, other) {
+			return false
+		}
 	}
 	return true
 }
 
 func TestNewAggregateController(t *testing.T) {
 	repo := &postStatsRepositoryMocking{}
-	controller := newAggregateController(repo)
+	controller := newAggregateController(repo, nil)
 	if controller.postStatsRepository != repo {
 		t.Error("repository missmatch")
 	}
@@ -73,7 +110,11 @@ func TestAggregateControllerAggregate(t *testing.T) {
 		shouldFail     bool
 		mock           iPostStatsRepository
 		duration       time.Duration
-		dimension      string
+		dimensions     []string
+		stats          bool
+		includeAbsent  bool
+		groupBy        string
+		platforms      PlatformFilter
 		expectedResult *PostsStatAggregation
 	}
 
@@ -85,16 +126,19 @@ func TestAggregateControllerAggregate(t *testing.T) {
 				returnError: false,
 				NoResults:   false,
 			},
-			duration:  5 * time.Second,
-			dimension: "likes",
+			duration:   5 * time.Second,
+			dimensions: []string{"likes"},
 			expectedResult: &PostsStatAggregation{
-				TotalPosts:       2,
-				MinimumTimestamp: 5,
-				MaximumTimestamp: 11,
-				AvgLikes:         intP(4),
-				AvgComments:      nil,
-				AvgFavorites:     nil,
-				AvgRetweets:      nil,
+				PostsStats: PostsStats{
+					TotalPosts:       2,
+					MinimumTimestamp: 5,
+					MaximumTimestamp: 11,
+					SampleSize:       map[string]int{"likes": 2},
+					Averages:         map[string]float64{"likes": 4},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
 			},
 		},
 		{
@@ -104,16 +148,19 @@ func TestAggregateControllerAggregate(t *testing.T) {
 				returnError: false,
 				NoResults:   false,
 			},
-			duration:  5 * time.Second,
-			dimension: "comments",
+			duration:   5 * time.Second,
+			dimensions: []string{"comments"},
 			expectedResult: &PostsStatAggregation{
-				TotalPosts:       2,
-				MinimumTimestamp: 5,
-				MaximumTimestamp: 11,
-				AvgLikes:         nil,
-				AvgComments:      intP(5),
-				AvgFavorites:     nil,
-				AvgRetweets:      nil,
+				PostsStats: PostsStats{
+					TotalPosts:       2,
+					MinimumTimestamp: 5,
+					MaximumTimestamp: 11,
+					SampleSize:       map[string]int{"comments": 2},
+					Averages:         map[string]float64{"comments": 5},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
 			},
 		},
 		{
@@ -123,16 +170,19 @@ func TestAggregateControllerAggregate(t *testing.T) {
 				returnError: false,
 				NoResults:   false,
 			},
-			duration:  5 * time.Second,
-			dimension: "retweets",
+			duration:   5 * time.Second,
+			dimensions: []string{"retweets"},
 			expectedResult: &PostsStatAggregation{
-				TotalPosts:       2,
-				MinimumTimestamp: 5,
-				MaximumTimestamp: 11,
-				AvgLikes:         nil,
-				AvgComments:      nil,
-				AvgFavorites:     nil,
-				AvgRetweets:      intP(7),
+				PostsStats: PostsStats{
+					TotalPosts:       2,
+					MinimumTimestamp: 5,
+					MaximumTimestamp: 11,
+					SampleSize:       map[string]int{"retweets": 2},
+					Averages:         map[string]float64{"retweets": 7},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
 			},
 		},
 		{
@@ -142,17 +192,226 @@ func TestAggregateControllerAggregate(t *testing.T) {
 				returnError: false,
 				NoResults:   false,
 			},
-			duration:  5 * time.Second,
-			dimension: "favorites",
+			duration:   5 * time.Second,
+			dimensions: []string{"favorites"},
+			expectedResult: &PostsStatAggregation{
+				PostsStats: PostsStats{
+					TotalPosts:       2,
+					MinimumTimestamp: 5,
+					MaximumTimestamp: 11,
+					SampleSize:       map[string]int{"favorites": 2},
+					Averages:         map[string]float64{"favorites": 6},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
+			},
+		},
+		{
+			name:       "Success case with every dimension",
+			shouldFail: false,
+			mock: &postStatsRepositoryMocking{
+				returnError: false,
+				NoResults:   false,
+			},
+			duration:   5 * time.Second,
+			dimensions: []string{"likes", "comments", "favorites", "retweets"},
+			expectedResult: &PostsStatAggregation{
+				PostsStats: PostsStats{
+					TotalPosts:       2,
+					MinimumTimestamp: 5,
+					MaximumTimestamp: 11,
+					SampleSize:       map[string]int{"likes": 2, "comments": 2, "favorites": 2, "retweets": 2},
+					Averages:         map[string]float64{"likes": 4, "comments": 5, "favorites": 6, "retweets": 7},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
+			},
+		},
+		{
+			name:       "Success case with stats",
+			shouldFail: false,
+			mock: &postStatsRepositoryMocking{
+				returnError: false,
+				NoResults:   false,
+			},
+			duration:   5 * time.Second,
+			dimensions: []string{"likes", "comments"},
+			stats:      true,
 			expectedResult: &PostsStatAggregation{
-				TotalPosts:       2,
-				MinimumTimestamp: 5,
-				MaximumTimestamp: 11,
-				AvgLikes:         nil,
-				AvgComments:      nil,
-				AvgFavorites:     intP(6),
-				AvgRetweets:      nil,
+				PostsStats: PostsStats{
+					TotalPosts:       2,
+					MinimumTimestamp: 5,
+					MaximumTimestamp: 11,
+					SampleSize:       map[string]int{"likes": 2, "comments": 2},
+					Averages:         map[string]float64{"likes": 4, "comments": 5},
+					Stats: map[string]DimensionStats{
+						"likes": {
+							Count:  2,
+							Sum:    Sum{Integer: 8},
+							Min:    1,
+							Max:    7,
+							Mean:   4,
+							Median: 4,
+							P90:    6.4,
+							P95:    6.7,
+							P99:    6.94,
+							StdDev: 3,
+						},
+						"comments": {
+							Count:  2,
+							Sum:    Sum{Integer: 10},
+							Min:    2,
+							Max:    8,
+							Mean:   5,
+							Median: 5,
+							P90:    7.4,
+							P95:    7.7,
+							P99:    7.94,
+							StdDev: 3,
+						},
+					},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
+			},
+		},
+		{
+			name:       "Success case: posts without the dimension are left out of its average",
+			shouldFail: false,
+			mock: &postStatsRepositoryMocking{
+				AbsentMetrics: true,
+			},
+			duration:   5 * time.Second,
+			dimensions: []string{"likes", "retweets"},
+			expectedResult: &PostsStatAggregation{
+				PostsStats: PostsStats{
+					TotalPosts:       3,
+					MinimumTimestamp: 5,
+					MaximumTimestamp: 11,
+					SampleSize:       map[string]int{"likes": 3, "retweets": 2},
+					Averages:         map[string]float64{"likes": 3, "retweets": 7},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
+			},
+		},
+		{
+			name:       "Success case: posts without the dimension count as 0",
+			shouldFail: false,
+			mock: &postStatsRepositoryMocking{
+				AbsentMetrics: true,
+			},
+			duration:      5 * time.Second,
+			dimensions:    []string{"retweets"},
+			includeAbsent: true,
+			expectedResult: &PostsStatAggregation{
+				PostsStats: PostsStats{
+					TotalPosts:       3,
+					MinimumTimestamp: 5,
+					MaximumTimestamp: 11,
+					SampleSize:       map[string]int{"retweets": 2},
+					Averages:         map[string]float64{"retweets": 14. / 3},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
+			},
+		},
+		{
+			name:       "Success case: grouped by platform",
+			shouldFail: false,
+			mock: &postStatsRepositoryMocking{
+				AbsentMetrics: true,
+			},
+			duration:   5 * time.Second,
+			dimensions: []string{"likes", "retweets"},
+			groupBy:    GroupByPlatform,
+			expectedResult: &PostsStatAggregation{
+				PostsStats: PostsStats{
+					TotalPosts:       3,
+					MinimumTimestamp: 5,
+					MaximumTimestamp: 11,
+					SampleSize:       map[string]int{"likes": 3, "retweets": 2},
+					Averages:         map[string]float64{"likes": 3, "retweets": 7},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
+				Platforms: map[string]*PostsStats{
+					"tweet": {
+						TotalPosts:       2,
+						MinimumTimestamp: 5,
+						MaximumTimestamp: 11,
+						SampleSize:       map[string]int{"likes": 2, "retweets": 2},
+						Averages:         map[string]float64{"likes": 4, "retweets": 7},
+					},
+					"youtube_video": {
+						TotalPosts:       1,
+						MinimumTimestamp: 8,
+						MaximumTimestamp: 8,
+						SampleSize:       map[string]int{"likes": 1, "retweets": 0},
+						Averages:         map[string]float64{"likes": 1, "retweets": 0},
+					},
+				},
+			},
+		},
+		{
+			name:       "Success case: filtered by platform",
+			shouldFail: false,
+			mock: &postStatsRepositoryMocking{
+				AbsentMetrics: true,
+			},
+			duration:   5 * time.Second,
+			dimensions: []string{"likes"},
+			platforms:  PlatformFilter{Exclude: []string{"tweet"}},
+			expectedResult: &PostsStatAggregation{
+				PostsStats: PostsStats{
+					TotalPosts:       1,
+					MinimumTimestamp: 8,
+					MaximumTimestamp: 8,
+					SampleSize:       map[string]int{"likes": 1},
+					Averages:         map[string]float64{"likes": 1},
+				},
+				DroppedEvents: 3,
+				DecodeErrors:  1,
+				SkippedEvents: 2,
+			},
+		},
+		{
+			name:       "Fail case: no post of the platform",
+			shouldFail: true,
+			mock: &postStatsRepositoryMocking{
+				returnError: false,
+				NoResults:   false,
 			},
+			duration:   5 * time.Second,
+			dimensions: []string{"likes"},
+			platforms:  PlatformFilter{Include: []string{"pin"}},
+		},
+		{
+			name:       "Fail case: unknown group",
+			shouldFail: true,
+			mock: &postStatsRepositoryMocking{
+				returnError: false,
+				NoResults:   false,
+			},
+			duration:   5 * time.Second,
+			dimensions: []string{"likes"},
+			groupBy:    "author",
+		},
+		{
+			name:       "Fail case: one unknown dimension",
+			shouldFail: true,
+			mock: &postStatsRepositoryMocking{
+				returnError: false,
+				NoResults:   false,
+			},
+			duration:   5 * time.Second,
+			dimensions: []string{"likes", "invalid"},
 		},
 		{
 			name:       "Fail case: repository returns an error",
@@ -161,8 +420,8 @@ func TestAggregateControllerAggregate(t *testing.T) {
 				returnError: true,
 				NoResults:   false,
 			},
-			duration:  5 * time.Second,
-			dimension: "likes",
+			duration:   5 * time.Second,
+			dimensions: []string{"likes"},
 		},
 		{
 			name:       "Fail case: repository returns 0 posts",
@@ -171,8 +430,8 @@ func TestAggregateControllerAggregate(t *testing.T) {
 				returnError: false,
 				NoResults:   true,
 			},
-			duration:  5 * time.Second,
-			dimension: "likes",
+			duration:   5 * time.Second,
+			dimensions: []string{"likes"},
 		},
 		{
 			name:       "Fail case: unknown dimension",
@@ -181,8 +440,8 @@ func TestAggregateControllerAggregate(t *testing.T) {
 				returnError: false,
 				NoResults:   false,
 			},
-			duration:  5 * time.Second,
-			dimension: "invalid",
+			duration:   5 * time.Second,
+			dimensions: []string{"invalid"},
 		},
 	}
 
@@ -190,8 +449,9 @@ func TestAggregateControllerAggregate(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			instance := &aggregateController{
 				postStatsRepository: testCase.mock,
+				registry:            testRegistry(t),
 			}
-			stats, err := instance.Aggregate(testCase.duration, testCase.dimension)
+			stats, err := instance.Aggregate(context.Background(), Query{Duration: testCase.duration, Dimensions: testCase.dimensions, Stats: testCase.stats, IncludeAbsent: testCase.includeAbsent, GroupBy: testCase.groupBy, Platforms: testCase.platforms})
 			if testCase.shouldFail {
 				if err == nil {
 					t.Errorf("expected an error, got nil")
@@ -208,73 +468,39 @@ func TestAggregateControllerAggregate(t *testing.T) {
 	}
 }
 
-func TestAggregateControllerComputeAvgLikes(t *testing.T) {
+func TestAggregateControllerComputeAvg(t *testing.T) {
 	type testData struct {
 		name           string
-		postsStats     []postStats
-		expectedResult int
+		values         []float64
+		derived        bool
+		expectedResult float64
 	}
 
 	testCases := [...]testData{
 		{
-			name: "Succes case",
-			postsStats: []postStats{
-				{
-					Likes: 2,
-				},
-				{
-					Likes: 2,
-				},
-			},
+			name:           "Succes case",
+			values:         []float64{2, 2},
 			expectedResult: 2,
 		},
 		{
-			name:           "Succes case: empty posts",
-			postsStats:     []postStats{},
-			expectedResult: 0,
+			name:           "Succes case: fractional average",
+			values:         []float64{1, 2},
+			expectedResult: 1.5,
 		},
-	}
-
-	for _, testCase := range testCases {
-		t.Run(testCase.name, func(t *testing.T) {
-			instance := &aggregateController{
-				postStatsRepository: &postStatsRepositoryMocking{
-					returnError: false,
-					NoResults:   false,
-				},
-			}
-
-			avgLikes := instance.computeAvgLikes(testCase.postsStats)
-			if avgLikes != testCase.expectedResult {
-				t.Errorf("expected %v, got %v", avgLikes, testCase.expectedResult)
-			}
-		})
-	}
-}
-
-func TestAggregateControllerComputeAvgComments(t *testing.T) {
-	type testData struct {
-		name           string
-		postsStats     []postStats
-		expectedResult int
-	}
-
-	testCases := [...]testData{
 		{
-			name: "Succes case",
-			postsStats: []postStats{
-				{
-					Comments: 2,
-				},
-				{
-					Comments: 2,
-				},
-			},
-			expectedResult: 2,
+			name:           "Succes case: large values",
+			values:         []float64{math.MaxInt32, math.MaxInt32},
+			expectedResult: math.MaxInt32,
 		},
 		{
-			name:           "Succes case: empty posts",
-			postsStats:     []postStats{},
+			name:           "Succes case: derived values",
+			values:         []float64{0.5, 0.25},
+			derived:        true,
+			expectedResult: 0.375,
+		},
+		{
+			name:           "Succes case: empty values",
+			values:         []float64{},
 			expectedResult: 0,
 		},
 	}
@@ -282,87 +508,109 @@ func TestAggregateControllerComputeAvgComments(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			instance := &aggregateController{
-				postStatsRepository: &postStatsRepositoryMocking{
-					returnError: false,
-					NoResults:   false,
-				},
+				postStatsRepository: &postStatsRepositoryMocking{},
 			}
 
-			avgComments := instance.computeAvgComments(testCase.postsStats)
-			if avgComments != testCase.expectedResult {
-				t.Errorf("expected %v, got %v", avgComments, testCase.expectedResult)
+			avg := instance.computeAvg(testCase.values, testCase.derived)
+			if avg != testCase.expectedResult {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, avg)
 			}
 		})
 	}
 }
 
-func TestAggregateControllerComputeAvgFavorites(t *testing.T) {
+func TestAggregateControllerSamples(t *testing.T) {
 	type testData struct {
-		name           string
-		postsStats     []postStats
-		expectedResult int
+		name               string
+		dimension          string
+		includeAbsent      bool
+		expectedResult     []float64
+		expectedSampleSize int
+	}
+
+	postsStats := []postStats{
+		{Metrics: map[string]float64{"likes": 3, "retweets": 0}},
+		{Metrics: map[string]float64{"likes": 5}},
+		{Metrics: map[string]float64{"likes": 1, "retweets": 4}},
 	}
 
 	testCases := [...]testData{
 		{
-			name: "Succes case",
-			postsStats: []postStats{
-				{
-					Favorites: 2,
-				},
-				{
-					Favorites: 2,
-				},
-			},
-			expectedResult: 2,
+			name:               "Succes case: dimension carried by every post",
+			dimension:          "likes",
+			expectedResult:     []float64{3, 5, 1},
+			expectedSampleSize: 3,
 		},
 		{
-			name:           "Succes case: empty posts",
-			postsStats:     []postStats{},
-			expectedResult: 0,
+			name:               "Succes case: absent dimension left out, zero kept",
+			dimension:          "retweets",
+			expectedResult:     []float64{0, 4},
+			expectedSampleSize: 2,
+		},
+		{
+			name:               "Succes case: absent dimension counted as 0",
+			dimension:          "retweets",
+			includeAbsent:      true,
+			expectedResult:     []float64{0, 0, 4},
+			expectedSampleSize: 2,
+		},
+		{
+			name:               "Succes case: dimension carried by no post",
+			dimension:          "comments",
+			expectedResult:     []float64{},
+			expectedSampleSize: 0,
 		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			instance := &aggregateController{
-				postStatsRepository: &postStatsRepositoryMocking{
-					returnError: false,
-					NoResults:   false,
-				},
+				postStatsRepository: &postStatsRepositoryMocking{},
 			}
 
-			avgFavorites := instance.computeAvgFavorites(testCase.postsStats)
-			if avgFavorites != testCase.expectedResult {
-				t.Errorf("expected %v, got %v", avgFavorites, testCase.expectedResult)
+			values := instance.samples(postsStats, testCase.dimension, testCase.includeAbsent)
+			if !slices.Equal(values, testCase.expectedResult) {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, values)
+			}
+
+			if size := instance.sampleSize(postsStats, testCase.dimension); size != testCase.expectedSampleSize {
+				t.Errorf("expected sample size %v, got %v", testCase.expectedSampleSize, size)
 			}
 		})
 	}
 }
 
-func TestAggregateControllerComputeAvgRetweets(t *testing.T) {
+func TestAggregateControllerRoundAvg(t *testing.T) {
 	type testData struct {
 		name           string
-		postsStats     []postStats
-		expectedResult int
+		average        float64
+		query          Query
+		expectedResult float64
 	}
 
 	testCases := [...]testData{
 		{
-			name: "Succes case",
-			postsStats: []postStats{
-				{
-					Retweets: 2,
-				},
-				{
-					Retweets: 2,
-				},
-			},
-			expectedResult: 2,
+			name:           "Succes case: full precision by default",
+			average:        0.123456,
+			query:          Query{},
+			expectedResult: 0.123456,
+		},
+		{
+			name:           "Succes case: rounded to precision",
+			average:        0.125,
+			query:          Query{Precision: intP(2)},
+			expectedResult: 0.13,
+		},
+		{
+			name:           "Succes case: rounded to integer",
+			average:        0.9,
+			query:          Query{Precision: intP(0)},
+			expectedResult: 1,
 		},
 		{
-			name:           "Succes case: empty posts",
-			postsStats:     []postStats{},
+			name:           "Succes case: integer averages are truncated",
+			average:        0.9,
+			query:          Query{IntegerAverages: true, Precision: intP(2)},
 			expectedResult: 0,
 		},
 	}
@@ -370,26 +618,17 @@ func TestAggregateControllerComputeAvgRetweets(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			instance := &aggregateController{
-				postStatsRepository: &postStatsRepositoryMocking{
-					returnError: false,
-					NoResults:   false,
-				},
+				postStatsRepository: &postStatsRepositoryMocking{},
 			}
 
-			avgRetweets := instance.computeAvgRetweets(testCase.postsStats)
-			if avgRetweets != testCase.expectedResult {
-				t.Errorf("expected %v, got %v", avgRetweets, testCase.expectedResult)
+			avg := instance.roundAvg(testCase.average, testCase.query)
+			if avg != testCase.expectedResult {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, avg)
 			}
 		})
 	}
 }
 
-func TestIntP(t *testing.T) {
-	i := 2
-
-	iP := intP(i)
-
-	if i != *iP {
-		t.Errorf("expected %v, got %v", i, *iP)
-	}
+func intP(i int) *int {
+	return &i
 }
@@ -0,0 +1,160 @@
+package aggregate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
+)
+
+// DefaultUnit is the unit of the dimensions that don't declare one.
+const DefaultUnit = "count"
+
+var ErrInvalidDimensions = errors.New("invalid dimensions")
+
+// DefaultDimensions are the dimensions analysed when none is configured.
+var DefaultDimensions = []Dimension{
+	{Name: "likes"},
+	{Name: "comments"},
+	{Name: "favorites", Platforms: []string{"tweet"}},
+	{Name: "retweets", Platforms: []string{"tweet"}},
+}
+
+// Dimension declares a numeric field of the posts that can be analysed, or a dimension derived from others.
+type Dimension struct {
+	// Name of the dimension in the queries, and in the avg_<name> fields of the responses.
+	Name string `json:"name"`
+
+	// Field is the name of the post field holding the dimension in the events, the dimension name if empty.
+	Field string `json:"field,omitempty"`
+
+	// Expression derives the dimension from the dimensions declared before it, e.g. (likes + comments) / followers.
+	// It is made of numbers, dimension names, the + - * / operators, parentheses and coalesce calls,
+	// coalesce(shares, 0) being shares if the post carries it and 0 otherwise. A derived dimension is absent
+	// from the posts lacking one of its operands, or in which it divides by zero. It excludes Field.
+	Expression string `json:"expression,omitempty"`
+
+	// Platforms lists the platforms supplying the dimension, all of them if empty.
+	// The field is ignored in the posts of other platforms.
+	Platforms []string `json:"platforms,omitempty"`
+
+	// Unit of the dimension values, DefaultUnit if empty.
+	Unit string `json:"unit,omitempty"`
+
+	// expression is the compiled Expression, nil for the raw dimensions.
+	expression expression
+}
+
+// FieldName returns the name of the post field holding the dimension.
+func (d Dimension) FieldName() string {
+	if d.Field == "" {
+		return d.Name
+	}
+
+	return d.Field
+}
+
+// SuppliedBy reports whether the posts of platform supply the dimension.
+func (d Dimension) SuppliedBy(platform string) bool {
+	return len(d.Platforms) == 0 || slices.Contains(d.Platforms, platform)
+}
+
+// Derived reports whether the dimension is computed from other dimensions.
+func (d Dimension) Derived() bool {
+	return d.Expression != ""
+}
+
+// Extract returns the value of the dimension in post, false if the post doesn't carry it. values holds
+// the dimensions carried by the post among those declared before d, from which a derived dimension is computed.
+func (d Dimension) Extract(post *posts.Post, values map[string]float64) (float64, bool) {
+	if !d.SuppliedBy(post.Platform) {
+		return 0, false
+	}
+
+	if d.expression != nil {
+		return d.expression.eval(values)
+	}
+
+	value, ok := post.Metrics[d.FieldName()]
+
+	return float64(value), ok
+}
+
+// Registry holds the dimensions that can be analysed. It is the single source of truth of the queryable
+// dimensions, shared by the validation of the requests, the aggregation and the API description.
+type Registry struct {
+	dimensions []Dimension
+	byName     map[string]Dimension
+}
+
+// NewRegistry returns the registry of the declared dimensions, DefaultDimensions if none is declared.
+// The dimensions must have unique non-empty names, and the derived ones valid expressions
+// referencing the dimensions declared before them, which rules out cycles.
+func NewRegistry(declared []Dimension) (*Registry, error) {
+	if len(declared) == 0 {
+		declared = DefaultDimensions
+	}
+
+	registry := &Registry{
+		dimensions: make([]Dimension, 0, len(declared)),
+		byName:     make(map[string]Dimension, len(declared)),
+	}
+
+	for _, dimension := range declared {
+		if dimension.Name == "" {
+			return nil, fmt.Errorf("%w: dimension without name", ErrInvalidDimensions)
+		}
+
+		if _, ok := registry.byName[dimension.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate dimension %s", ErrInvalidDimensions, dimension.Name)
+		}
+
+		if dimension.Derived() {
+			if dimension.Field != "" {
+				return nil, fmt.Errorf("%w: dimension %s has both a field and an expression", ErrInvalidDimensions, dimension.Name)
+			}
+
+			expression, err := parseExpression(dimension.Expression, func(name string) bool {
+				_, ok := registry.byName[name]
+				return ok
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%w: dimension %s: %w", ErrInvalidDimensions, dimension.Name, err)
+			}
+
+			dimension.expression = expression
+		}
+
+		if dimension.Unit == "" {
+			dimension.Unit = DefaultUnit
+		}
+
+		registry.dimensions = append(registry.dimensions, dimension)
+		registry.byName[dimension.Name] = dimension
+	}
+
+	return registry, nil
+}
+
+// Lookup returns the dimension called name, false if there is none.
+func (r *Registry) Lookup(name string) (Dimension, bool) {
+	dimension, ok := r.byName[name]
+
+	return dimension, ok
+}
+
+// Dimensions returns the dimensions in their declaration order.
+func (r *Registry) Dimensions() []Dimension {
+	return slices.Clone(r.dimensions)
+}
+
+// Names returns the names of the dimensions in their declaration order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.dimensions))
+	for _, dimension := range r.dimensions {
+		names = append(names, dimension.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,211 @@
+package aggregate
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
+)
+
+// testRegistry returns the registry of the default dimensions.
+func testRegistry(t *testing.T) *Registry {
+	t.Helper()
+
+	registry, err := NewRegistry(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return registry
+}
+
+func TestNewRegistry(t *testing.T) {
+	type testData struct {
+		name          string
+		declared      []Dimension
+		shouldFail    bool
+		expectedNames []string
+	}
+
+	testCases := [...]testData{
+		{
+			name:          "Success case: default dimensions",
+			declared:      nil,
+			expectedNames: []string{"likes", "comments", "favorites", "retweets"},
+		},
+		{
+			name: "Success case: custom dimensions in declaration order",
+			declared: []Dimension{
+				{Name: "shares"},
+				{Name: "plays", Field: "views", Platforms: []string{"tiktok_video"}},
+			},
+			expectedNames: []string{"shares", "plays"},
+		},
+		{
+			name: "Success case: derived dimensions",
+			declared: []Dimension{
+				{Name: "likes"},
+				{Name: "comments"},
+				{Name: "engagement", Expression: "likes + comments"},
+				{Name: "comment_to_like_ratio", Expression: "comments / likes", Unit: "ratio"},
+			},
+			expectedNames: []string{"likes", "comments", "engagement", "comment_to_like_ratio"},
+		},
+		{
+			name:       "Fail case: dimension without name",
+			declared:   []Dimension{{Field: "views"}},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: duplicate dimension",
+			declared:   []Dimension{{Name: "shares"}, {Name: "shares", Field: "reposts"}},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: invalid expression",
+			declared:   []Dimension{{Name: "likes"}, {Name: "engagement", Expression: "likes +"}},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: expression referencing an undeclared dimension",
+			declared:   []Dimension{{Name: "likes"}, {Name: "engagement", Expression: "likes + shares"}},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: expression referencing a later dimension",
+			declared:   []Dimension{{Name: "engagement", Expression: "likes + 1"}, {Name: "likes"}},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: self-referencing expression",
+			declared:   []Dimension{{Name: "likes"}, {Name: "engagement", Expression: "engagement + likes"}},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: both a field and an expression",
+			declared:   []Dimension{{Name: "likes"}, {Name: "engagement", Field: "likes", Expression: "likes"}},
+			shouldFail: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			registry, err := NewRegistry(testCase.declared)
+			if testCase.shouldFail {
+				if !errors.Is(err, ErrInvalidDimensions) {
+					t.Errorf("expected error %v, got %v", ErrInvalidDimensions, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if names := registry.Names(); !slices.Equal(names, testCase.expectedNames) {
+				t.Errorf("expected %v, got %v", testCase.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestRegistryLookup(t *testing.T) {
+	registry, err := NewRegistry([]Dimension{{Name: "plays", Field: "views", Unit: "seconds"}, {Name: "shares"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	plays, ok := registry.Lookup("plays")
+	if !ok || plays.FieldName() != "views" || plays.Unit != "seconds" {
+		t.Errorf("expected the plays dimension, got %+v", plays)
+	}
+
+	if shares, _ := registry.Lookup("shares"); shares.Unit != DefaultUnit || shares.FieldName() != "shares" {
+		t.Errorf("expected the default field and unit, got %+v", shares)
+	}
+
+	if _, ok := registry.Lookup("likes"); ok {
+		t.Errorf("expected an undeclared dimension to be unknown")
+	}
+}
+
+func TestDimensionExtract(t *testing.T) {
+	type testData struct {
+		name            string
+		dimension       Dimension
+		post            *posts.Post
+		values          map[string]float64
+		expectedValue   float64
+		expectedPresent bool
+	}
+
+	testCases := [...]testData{
+		{
+			name:            "Success case: carried dimension",
+			dimension:       Dimension{Name: "likes"},
+			post:            &posts.Post{Platform: "pin", Metrics: map[string]int64{"likes": 3}},
+			expectedValue:   3,
+			expectedPresent: true,
+		},
+		{
+			name:            "Success case: zero is carried",
+			dimension:       Dimension{Name: "likes"},
+			post:            &posts.Post{Platform: "pin", Metrics: map[string]int64{"likes": 0}},
+			expectedValue:   0,
+			expectedPresent: true,
+		},
+		{
+			name:            "Success case: absent field",
+			dimension:       Dimension{Name: "likes"},
+			post:            &posts.Post{Platform: "pin", Metrics: map[string]int64{"comments": 3}},
+			expectedPresent: false,
+		},
+		{
+			name:            "Success case: field of another platform",
+			dimension:       Dimension{Name: "retweets", Platforms: []string{"tweet"}},
+			post:            &posts.Post{Platform: "pin", Metrics: map[string]int64{"retweets": 3}},
+			expectedPresent: false,
+		},
+		{
+			name:            "Success case: renamed field",
+			dimension:       Dimension{Name: "plays", Field: "views"},
+			post:            &posts.Post{Platform: "tiktok_video", Metrics: map[string]int64{"views": 5}},
+			expectedValue:   5,
+			expectedPresent: true,
+		},
+		{
+			name: "Success case: derived dimension",
+			dimension: Dimension{
+				Name:       "comment_to_like_ratio",
+				Expression: "comments / likes",
+				expression: binary{operator: '/', left: reference("comments"), right: reference("likes")},
+			},
+			post:            &posts.Post{Platform: "pin", Metrics: map[string]int64{"likes": 4, "comments": 1}},
+			values:          map[string]float64{"likes": 4, "comments": 1},
+			expectedValue:   0.25,
+			expectedPresent: true,
+		},
+		{
+			name: "Success case: derived dimension of another platform",
+			dimension: Dimension{
+				Name:       "retweet_to_like_ratio",
+				Expression: "retweets / likes",
+				Platforms:  []string{"tweet"},
+				expression: binary{operator: '/', left: reference("retweets"), right: reference("likes")},
+			},
+			post:            &posts.Post{Platform: "pin", Metrics: map[string]int64{"likes": 4, "retweets": 1}},
+			values:          map[string]float64{"likes": 4, "retweets": 1},
+			expectedPresent: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, ok := testCase.dimension.Extract(testCase.post, testCase.values)
+			if ok != testCase.expectedPresent || value != testCase.expectedValue {
+				t.Errorf("expected %v %v, got %v %v", testCase.expectedValue, testCase.expectedPresent, value, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package aggregate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// coalesceFunction returns its first argument carried by the post, e.g. coalesce(shares, 0).
+const coalesceFunction = "coalesce"
+
+var ErrInvalidExpression = errors.New("invalid expression")
+
+// expression is a compiled derived dimension expression. It is evaluated against the values of the dimensions
+// carried by a post, and is absent from the post if one of its operands is, or if it divides by zero.
+type expression interface {
+	eval(values map[string]float64) (float64, bool)
+}
+
+type (
+	number    float64
+	reference string
+	negation  struct{ operand expression }
+	binary    struct {
+		operator    rune
+		left, right expression
+	}
+	coalesce struct{ arguments []expression }
+)
+
+func (n number) eval(map[string]float64) (float64, bool) {
+	return float64(n), true
+}
+
+func (r reference) eval(values map[string]float64) (float64, bool) {
+	value, ok := values[string(r)]
+
+	return value, ok
+}
+
+func (n negation) eval(values map[string]float64) (float64, bool) {
+	value, ok := n.operand.eval(values)
+
+	return -value, ok
+}
+
+func (b binary) eval(values map[string]float64) (float64, bool) {
+	left, ok := b.left.eval(values)
+	if !ok {
+		return 0, false
+	}
+
+	right, ok := b.right.eval(values)
+	if !ok {
+		return 0, false
+	}
+
+	switch b.operator {
+	case '+':
+		return left + right, true
+	case '-':
+		return left - right, true
+	case '*':
+		return left * right, true
+	default:
+		if right == 0 {
+			return 0, false
+		}
+
+		return left / right, true
+	}
+}
+
+func (c coalesce) eval(values map[string]float64) (float64, bool) {
+	for _, argument := range c.arguments {
+		if value, ok := argument.eval(values); ok {
+			return value, true
+		}
+	}
+
+	return 0, false
+}
+
+// parseExpression compiles input, made of numbers, dimension names, the + - * / operators, parentheses
+// and coalesce calls. Every dimension it references must be known.
+func parseExpression(input string, known func(name string) bool) (expression, error) {
+	p := &parser{input: []rune(input), known: known}
+
+	expr, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.skipSpaces(); p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+
+	return expr, nil
+}
+
+// parser is a recursive descent parser of expressions, with the usual operator precedence.
+type parser struct {
+	input []rune
+	pos   int
+	known func(name string) bool
+}
+
+// parseSum parses sum := product (("+" | "-") product)*.
+func (p *parser) parseSum() (expression, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept('+') || p.accept('-') {
+		operator := p.input[p.pos-1]
+
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binary{operator: operator, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseProduct parses product := operand (("*" | "/") operand)*.
+func (p *parser) parseProduct() (expression, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept('*') || p.accept('/') {
+		operator := p.input[p.pos-1]
+
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		left = binary{operator: operator, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseOperand parses operand := "-" operand | "(" sum ")" | number | name | name "(" sum ("," sum)* ")".
+func (p *parser) parseOperand() (expression, error) {
+	p.skipSpaces()
+
+	switch {
+	case p.pos >= len(p.input):
+		return nil, p.errorf("unexpected end")
+	case p.accept('-'):
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		return negation{operand: operand}, nil
+	case p.accept('('):
+		expr, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.accept(')') {
+			return nil, p.errorf("missing )")
+		}
+
+		return expr, nil
+	case unicode.IsDigit(p.input[p.pos]) || p.input[p.pos] == '.':
+		return p.parseNumber()
+	case isNameRune(p.input[p.pos]):
+		return p.parseName()
+	default:
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+}
+
+func (p *parser) parseNumber() (expression, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (unicode.IsDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+		p.pos++
+	}
+
+	value, err := strconv.ParseFloat(string(p.input[start:p.pos]), 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %q", string(p.input[start:p.pos]))
+	}
+
+	return number(value), nil
+}
+
+func (p *parser) parseName() (expression, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (isNameRune(p.input[p.pos]) || unicode.IsDigit(p.input[p.pos])) {
+		p.pos++
+	}
+
+	name := string(p.input[start:p.pos])
+
+	if !p.accept('(') {
+		if !p.known(name) {
+			return nil, p.errorf("unknown dimension %s", name)
+		}
+
+		return reference(name), nil
+	}
+
+	if name != coalesceFunction {
+		return nil, p.errorf("unknown function %s", name)
+	}
+
+	arguments := []expression{}
+	for {
+		argument, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+
+		arguments = append(arguments, argument)
+
+		if p.accept(')') {
+			return coalesce{arguments: arguments}, nil
+		}
+
+		if !p.accept(',') {
+			return nil, p.errorf("missing )")
+		}
+	}
+}
+
+// accept consumes the next rune, spaces aside, if it is r.
+func (p *parser) accept(r rune) bool {
+	p.skipSpaces()
+
+	if p.pos < len(p.input) && p.input[p.pos] == r {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w %q at %d: %s", ErrInvalidExpression, strings.TrimSpace(string(p.input)), p.pos, fmt.Sprintf(format, args...))
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
@@ -0,0 +1,154 @@
+package aggregate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	type testData struct {
+		name            string
+		input           string
+		values          map[string]float64
+		shouldFail      bool
+		expectedValue   float64
+		expectedPresent bool
+	}
+
+	values := map[string]float64{"likes": 6, "comments": 3, "shares": 1, "followers": 100, "views": 0}
+
+	testCases := [...]testData{
+		{
+			name:            "Success case: sum",
+			input:           "likes + comments + shares",
+			values:          values,
+			expectedValue:   10,
+			expectedPresent: true,
+		},
+		{
+			name:            "Success case: ratio",
+			input:           "comments/likes",
+			values:          values,
+			expectedValue:   0.5,
+			expectedPresent: true,
+		},
+		{
+			name:            "Success case: precedence",
+			input:           "likes + comments * 2 - shares",
+			values:          values,
+			expectedValue:   11,
+			expectedPresent: true,
+		},
+		{
+			name:            "Success case: left associativity",
+			input:           "likes - comments - shares",
+			values:          values,
+			expectedValue:   2,
+			expectedPresent: true,
+		},
+		{
+			name:            "Success case: parentheses and numbers",
+			input:           "(likes + comments + shares) / followers * 100",
+			values:          values,
+			expectedValue:   10,
+			expectedPresent: true,
+		},
+		{
+			name:            "Success case: negation",
+			input:           "-(likes - 0.5)",
+			values:          values,
+			expectedValue:   -5.5,
+			expectedPresent: true,
+		},
+		{
+			name:            "Success case: absent operand",
+			input:           "likes + saves",
+			values:          values,
+			expectedPresent: false,
+		},
+		{
+			name:            "Success case: division by zero",
+			input:           "likes / views",
+			values:          values,
+			expectedPresent: false,
+		},
+		{
+			name:            "Success case: coalesce of an absent dimension",
+			input:           "likes + coalesce(saves, shares, 0)",
+			values:          values,
+			expectedValue:   7,
+			expectedPresent: true,
+		},
+		{
+			name:            "Success case: coalesce of absent dimensions",
+			input:           "coalesce(saves, likes / views)",
+			values:          values,
+			expectedPresent: false,
+		},
+		{
+			name:       "Fail case: empty expression",
+			input:      " ",
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: missing operand",
+			input:      "likes *",
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: unbalanced parentheses",
+			input:      "(likes + comments",
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: trailing input",
+			input:      "likes comments",
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: unknown dimension",
+			input:      "likes + reposts",
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: unknown function",
+			input:      "max(likes, comments)",
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: invalid number",
+			input:      "likes * 1.2.3",
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: unexpected character",
+			input:      "likes % 2",
+			shouldFail: true,
+		},
+	}
+
+	known := func(name string) bool {
+		return name != "reposts"
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			expression, err := parseExpression(testCase.input, known)
+			if testCase.shouldFail {
+				if !errors.Is(err, ErrInvalidExpression) {
+					t.Errorf("expected error %v, got %v", ErrInvalidExpression, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			value, ok := expression.eval(testCase.values)
+			if ok != testCase.expectedPresent || value != testCase.expectedValue && ok {
+				t.Errorf("expected %v %v, got %v %v", testCase.expectedValue, testCase.expectedPresent, value, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package aggregate
+
+import "slices"
+
+// PlatformFilter selects the posts by the platform they have been published on.
+type PlatformFilter struct {
+	// Include keeps only the posts of these platforms, all the platforms if empty.
+	Include []string
+
+	// Exclude leaves out the posts of these platforms.
+	Exclude []string
+}
+
+// Match reports whether the posts of platform pass the filter.
+func (f PlatformFilter) Match(platform string) bool {
+	if len(f.Include) > 0 && !slices.Contains(f.Include, platform) {
+		return false
+	}
+
+	return !slices.Contains(f.Exclude, platform)
+}
+
+// filterPlatforms returns the posts of postsStats passing filter.
+func filterPlatforms(postsStats []postStats, filter PlatformFilter) []postStats {
+	if len(filter.Include) == 0 && len(filter.Exclude) == 0 {
+		return postsStats
+	}
+
+	filtered := make([]postStats, 0, len(postsStats))
+	for _, stat := range postsStats {
+		if filter.Match(stat.Platform) {
+			filtered = append(filtered, stat)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,79 @@
+package aggregate
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPlatformFilterMatch(t *testing.T) {
+	type testData struct {
+		name           string
+		filter         PlatformFilter
+		platform       string
+		expectedResult bool
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: every platform by default",
+			filter:         PlatformFilter{},
+			platform:       "tweet",
+			expectedResult: true,
+		},
+		{
+			name:           "Success case: included platform",
+			filter:         PlatformFilter{Include: []string{"tweet", "pin"}},
+			platform:       "pin",
+			expectedResult: true,
+		},
+		{
+			name:           "Success case: platform not included",
+			filter:         PlatformFilter{Include: []string{"tweet"}},
+			platform:       "pin",
+			expectedResult: false,
+		},
+		{
+			name:           "Success case: excluded platform",
+			filter:         PlatformFilter{Exclude: []string{"tweet"}},
+			platform:       "tweet",
+			expectedResult: false,
+		},
+		{
+			name:           "Success case: included then excluded platform",
+			filter:         PlatformFilter{Include: []string{"tweet"}, Exclude: []string{"tweet"}},
+			platform:       "tweet",
+			expectedResult: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if match := testCase.filter.Match(testCase.platform); match != testCase.expectedResult {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, match)
+			}
+		})
+	}
+}
+
+func TestFilterPlatforms(t *testing.T) {
+	postsStats := []postStats{
+		{Platform: "tweet", Timestamp: 1},
+		{Platform: "pin", Timestamp: 2},
+		{Platform: "tweet", Timestamp: 3},
+	}
+
+	filtered := filterPlatforms(postsStats, PlatformFilter{Include: []string{"tweet"}})
+
+	timestamps := []int64{}
+	for _, stat := range filtered {
+		timestamps = append(timestamps, stat.Timestamp)
+	}
+
+	if !slices.Equal(timestamps, []int64{1, 3}) {
+		t.Errorf("expected the tweets, got %v", filtered)
+	}
+
+	if unfiltered := filterPlatforms(postsStats, PlatformFilter{}); len(unfiltered) != len(postsStats) {
+		t.Errorf("expected every post without filter, got %v", unfiltered)
+	}
+}
@@ -1,20 +1,120 @@
 package aggregate
 
+import "encoding/json"
+
+// postStats holds the metrics of a post.
 type postStats struct {
-	Likes     int   `json:"likes,omitempty"`
-	Comments  int   `json:"comments,omitempty"`
-	Favorites int   `json:"favorites,omitempty"`
-	Retweets  int   `json:"retweets,omitempty"`
-	Timestamp int64 `json:"timestamp"`
+	Platform  string `json:"platform"`
+	Timestamp int64  `json:"timestamp"`
+
+	// Metrics holds the dimensions carried by the post, keyed by dimension name. A dimension absent
+	// from the post is absent from the map, which is distinct from a dimension equal to 0.
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// value returns the metric of the post for dimension, false if the post doesn't carry it
+// or if the dimension is unknown.
+func (s postStats) value(dimension string) (float64, bool) {
+	value, ok := s.Metrics[dimension]
+
+	return value, ok
+}
+
+// postStatsWindow holds the posts collected during an analysis window.
+type postStatsWindow struct {
+	Posts []postStats
+
+	// DroppedEvents is the number of events lost because the window couldn't keep up with the stream.
+	DroppedEvents uint64
+
+	// DecodeErrors is the number of malformed events skipped.
+	DecodeErrors uint64
+
+	// SkippedEvents is the number of well-formed events skipped because they hold no single post.
+	SkippedEvents uint64
 }
 
+// PostsStatAggregation holds the statistics of the posts of an analysis window.
 type PostsStatAggregation struct {
+	PostsStats
+
+	DroppedEvents uint64 `json:"dropped_events"`
+	DecodeErrors  uint64 `json:"decode_errors"`
+	SkippedEvents uint64 `json:"skipped_events"`
+
+	// Platforms breaks the statistics down by platform, when the posts are grouped by platform.
+	Platforms map[string]*PostsStats `json:"platforms,omitempty"`
+}
+
+// PostsStats holds the statistics of a set of posts.
+type PostsStats struct {
 	TotalPosts       int   `json:"total_posts"`
 	MinimumTimestamp int64 `json:"minimum_timestamp"`
 	MaximumTimestamp int64 `json:"maximum_timestamp"`
 
-	AvgLikes     *int `json:"avg_likes,omitempty"`
-	AvgComments  *int `json:"avg_comments,omitempty"`
-	AvgFavorites *int `json:"avg_favorites,omitempty"`
-	AvgRetweets  *int `json:"avg_retweets,omitempty"`
+	// SampleSize is the number of posts carrying each requested dimension.
+	SampleSize map[string]int `json:"sample_size"`
+
+	// Averages holds the average of each requested dimension, keyed by dimension.
+	// They are flattened into avg_<dimension> fields by MarshalJSON.
+	Averages map[string]float64 `json:"-"`
+
+	// Stats holds the distribution of each requested dimension, when asked for.
+	Stats map[string]DimensionStats `json:"stats,omitempty"`
+}
+
+// MarshalJSON encodes the window statistics along with the window fields.
+func (a PostsStatAggregation) MarshalJSON() ([]byte, error) {
+	// The promoted MarshalJSON of PostsStats would otherwise leave out the window fields.
+	fields, err := a.PostsStats.fields()
+	if err != nil {
+		return nil, err
+	}
+
+	fields["dropped_events"] = a.DroppedEvents
+	fields["decode_errors"] = a.DecodeErrors
+	fields["skipped_events"] = a.SkippedEvents
+
+	if a.Platforms != nil {
+		fields["platforms"] = a.Platforms
+	}
+
+	return json.Marshal(fields)
+}
+
+// MarshalJSON encodes the statistics, with an avg_<dimension> field for each average.
+func (s PostsStats) MarshalJSON() ([]byte, error) {
+	fields, err := s.fields()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
+
+// fields returns the JSON fields of the statistics, keyed by name.
+func (s PostsStats) fields() (map[string]any, error) {
+	// plain doesn't have the MarshalJSON method, not to recurse.
+	type plain PostsStats
+
+	data, err := json.Marshal(plain(s))
+	if err != nil {
+		return nil, err
+	}
+
+	rawFields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &rawFields); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]any, len(rawFields)+len(s.Averages))
+	for name, value := range rawFields {
+		fields[name] = value
+	}
+
+	for dimension, average := range s.Averages {
+		fields["avg_"+dimension] = average
+	}
+
+	return fields, nil
 }
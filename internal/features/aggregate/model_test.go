@@ -0,0 +1,47 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPostsStatAggregationMarshalJSON(t *testing.T) {
+	aggregation := PostsStatAggregation{
+		PostsStats: PostsStats{
+			TotalPosts:       2,
+			MinimumTimestamp: 5,
+			MaximumTimestamp: 11,
+			SampleSize:       map[string]int{"likes": 2, "plays": 1},
+			Averages:         map[string]float64{"likes": 4, "plays": 2.5},
+		},
+		DroppedEvents: 3,
+		Platforms: map[string]*PostsStats{
+			"tiktok_video": {
+				TotalPosts: 1,
+				SampleSize: map[string]int{"plays": 1},
+				Averages:   map[string]float64{"plays": 2.5},
+			},
+		},
+	}
+
+	data, err := json.Marshal(aggregation)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedResult := `{"avg_likes":4,"avg_plays":2.5,"decode_errors":0,"dropped_events":3,"maximum_timestamp":11,"minimum_timestamp":5,` +
+		`"platforms":{"tiktok_video":{"avg_plays":2.5,"maximum_timestamp":0,"minimum_timestamp":0,"sample_size":{"plays":1},"total_posts":1}},` +
+		`"sample_size":{"likes":2,"plays":1},"skipped_events":0,"total_posts":2}`
+	if string(data) != expectedResult {
+		t.Errorf("expected %s, got %s", expectedResult, data)
+	}
+
+	decoded := PostsStatAggregation{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if decoded.TotalPosts != 2 || decoded.DroppedEvents != 3 {
+		t.Errorf("expected the fields to be decoded, got %+v", decoded)
+	}
+}
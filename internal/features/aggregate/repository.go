@@ -2,80 +2,126 @@ package aggregate
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
 )
 
 var (
 	_ iPostStatsRepository = (*postStatsRepository)(nil)
 
-	ErrTooManyPosts     = errors.New("too many posts returned from stream")
-	ErrEmptyEvent       = errors.New("empty event")
-	ErrClosedSubscriber = errors.New("subscriber channel is closed")
+	ErrClosedSubscriber    = errors.New("subscriber channel is closed")
+	ErrStreamUnavailable   = errors.New("stream is not available")
+	ErrLookbackUnavailable = errors.New("lookback exceeds the stream history")
 )
 
 type iPostStatsRepository interface {
-	ReadFor(duration time.Duration) ([]postStats, error)
+	ReadFor(ctx context.Context, query Query) (*postStatsWindow, error)
 }
 
 type postStatsRepository struct {
-	sseClient *sse.Client
+	bus              *posts.Bus
+	subscriberConfig posts.SubscriberConfig
+	strictDecoding   bool
+	registry         *Registry
 }
 
-func (r *postStatsRepository) ReadFor(duration time.Duration) ([]postStats, error) {
-	sub, err := r.sseClient.NewSubscriber()
-	if err != nil {
-		return nil, fmt.Errorf("can't subscribe to sse server: %w", err)
+// ReadFor collects the posts of the query source streamed during the past query lookback, then during the query duration.
+// It returns early with ctx error if ctx is done before the end of the window. ErrLookbackUnavailable is returned if the
+// past posts are not available anymore. The number of events dropped because of a slow consumption, by this window
+// or by the bus, is reported with the posts. So are the events that aren't valid posts, unless decoding is strict,
+// in which case the first of them fails the window.
+func (r *postStatsRepository) ReadFor(ctx context.Context, query Query) (*postStatsWindow, error) {
+	windowCtx, cancel := context.WithTimeout(ctx, query.Duration)
+	defer cancel()
+
+	subscriberConfig := r.subscriberConfig
+	if query.Lookback > 0 {
+		subscriberConfig.Replay = sse.Replay{Since: time.Now().Add(-query.Lookback)}
 	}
-	defer r.sseClient.RemoveSubscriber(sub.ID)
 
-	ctx, cancel := context.WithTimeout(context.Background(), duration)
-	defer cancel()
+	if query.Source != "" {
+		subscriberConfig.Filter.Predicate = fromSource(query.Source, subscriberConfig.Filter.Predicate)
+	}
 
-	postsStats := make([]postStats, 0)
+	sub, err := r.bus.Subscribe(windowCtx, subscriberConfig)
+	if errors.Is(err, sse.ErrReplayUnavailable) {
+		return nil, fmt.Errorf("%w: %w", ErrLookbackUnavailable, err)
+	}
 
-	for {
-		select {
-		case event, ok := <-sub.Channel:
-			if !ok {
-				return nil, ErrClosedSubscriber
-			}
+	if err != nil {
+		return nil, fmt.Errorf("can't subscribe to post bus: %w", err)
+	}
+	defer sub.Unsubscribe()
 
-			postStat, err := r.decodeEvent(event)
-			if err != nil {
-				return nil, fmt.Errorf("can't decode event: %w", err)
-			}
+	busDropped := r.bus.Dropped()
+	window := &postStatsWindow{
+		Posts: make([]postStats, 0),
+	}
 
-			postsStats = append(postsStats, *postStat)
-		case <-ctx.Done():
-			return postsStats, nil
+	for message := range sub.Events() {
+		switch {
+		case message.Err == nil:
+			window.Posts = append(window.Posts, newPostStats(message.Post, r.registry))
+		case r.strictDecoding:
+			return nil, message.Err
+		case posts.Skipped(message.Err):
+			window.SkippedEvents++
+		default:
+			window.DecodeErrors++
 		}
 	}
+
+	// The subscriber is closed when the window ends, once the buffered posts, replayed ones included, are read.
+	if windowCtx.Err() == nil {
+		return nil, ErrClosedSubscriber
+	}
+
+	window.DroppedEvents = sub.Dropped() + r.bus.Dropped() - busDropped
+
+	return r.windowResult(ctx, window)
 }
 
-func (r *postStatsRepository) decodeEvent(event []byte) (*postStats, error) {
-	rawPayload := make(map[string]json.RawMessage)
-	if err := json.Unmarshal(event, &rawPayload); err != nil {
-		return nil, fmt.Errorf("can't unmarshal event: %w", err)
+// windowResult returns the collected window if it ended normally,
+// or the parent context error if it has been cancelled.
+// An empty window is reported as ErrStreamUnavailable when the stream is stalled or down,
+// to tell it apart from a quiet stream.
+func (r *postStatsRepository) windowResult(ctx context.Context, window *postStatsWindow) (*postStatsWindow, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	// Event must contains only one entry
-	if len(rawPayload) != 1 {
-		return nil, ErrTooManyPosts
+	if len(window.Posts) == 0 && !r.bus.State().Ready() {
+		return nil, fmt.Errorf("%w: sse client is %s", ErrStreamUnavailable, r.bus.State())
 	}
 
-	for _, postPayload := range rawPayload {
-		postStat := postStats{}
-		if err := json.Unmarshal(postPayload, &postStat); err != nil {
-			return nil, fmt.Errorf("can't unmarshal post payload: %w", err)
-		}
+	return window, nil
+}
+
+// fromSource returns a predicate selecting the events of source that satisfy predicate, if any.
+func fromSource(source string, predicate func(sse.Event) bool) func(sse.Event) bool {
+	return func(event sse.Event) bool {
+		return event.Source == source && (predicate == nil || predicate(event))
+	}
+}
 
-		return &postStat, nil
+// newPostStats keeps the dimensions of registry carried by the post.
+func newPostStats(post *posts.Post, registry *Registry) postStats {
+	stats := postStats{
+		Platform:  post.Platform,
+		Timestamp: post.Timestamp,
+		Metrics:   make(map[string]float64, len(registry.dimensions)),
+	}
+
+	for _, dimension := range registry.dimensions {
+		// The derived dimensions are computed from the dimensions declared, hence extracted, before them.
+		if value, ok := dimension.Extract(post, stats.Metrics); ok {
+			stats.Metrics[dimension.Name] = value
+		}
 	}
 
-	return nil, ErrEmptyEvent
+	return stats
 }
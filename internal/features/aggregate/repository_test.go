@@ -1,11 +1,15 @@
 package aggregate
 
 import (
-	"net/http"
-	"net/http/httptest"
+	"context"
+	"errors"
+	"maps"
+	"runtime"
 	"testing"
 	"time"
 
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sources"
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
 )
@@ -15,151 +19,300 @@ var (
 		Level: "INFO",
 	})
 
-	eventData = `data: {"tweet":{"id":959084760,"content":"Wishing for the heat of summer ðŸ”¥ https://t.co/Ykb72ulGdR","retweets":19,"favorites":643,"timestamp":1681859460,"post_id":"1648464174270521347","is_retweet":false,"comments":24}}`
+	eventData = `{"tweet":{"id":959084760,"content":"Wishing for the heat of summer ðŸ”¥ https://t.co/Ykb72ulGdR","retweets":19,"favorites":643,"timestamp":1681859460,"post_id":"1648464174270521347","is_retweet":false,"comments":24}}`
 )
 
-func createSSEServerMock(interval time.Duration, data []byte) *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/event-stream")
-		flusher, ok := w.(http.Flusher)
-		if !ok {
-			w.WriteHeader(500)
+// publishEvery publishes the event to the source every interval until ctx is done.
+func publishEvery(ctx context.Context, source *sources.Memory, interval time.Duration, event sse.Event) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
 			return
+		case <-ticker.C:
+			source.Publish(event)
 		}
+	}
+}
 
-		for i := 0; i < 5; i++ {
-			_, _ = w.Write(append(data, []byte("\n\n")...))
-			flusher.Flush()
-			time.Sleep(interval)
-		}
-	}))
+// startBus runs a post bus over the source, it stops once the source is closed.
+func startBus(source sources.Source) *posts.Bus {
+	bus := posts.NewBus(posts.Config{}, source)
+	go func() {
+		_ = bus.Run(context.Background())
+	}()
+
+	return bus
 }
 
 func TestPostStatsRepositoryReadFor(t *testing.T) {
-	server := createSSEServerMock(1*time.Second, []byte(eventData))
-	defer server.Close()
+	source := sources.NewMemory(sse.FeedConfig{}, loggerInstance)
+	defer source.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go publishEvery(ctx, source, 10*time.Millisecond, sse.Event{Data: []byte(eventData)})
+
+	repo := postStatsRepository{
+		bus:      startBus(source),
+		registry: testRegistry(t),
+	}
+
+	posts, err := repo.ReadFor(context.Background(), Query{Duration: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
 
-	sseClientConfig := sse.Config{
-		ServerURL:               server.URL,
-		MaxReconnectionAttempts: 1,
+	if len(posts.Posts) == 0 {
+		t.Errorf("post stats should not be empty")
 	}
+}
 
-	sseClient := sse.NewSSEClient(sseClientConfig, loggerInstance)
+func TestPostStatsRepositoryReadForSource(t *testing.T) {
+	source := sources.NewMemory(sse.FeedConfig{}, loggerInstance)
+	defer source.Close()
 
-	var listenErr error
-	go func() {
-		listenErr = sseClient.Listen()
-	}()
-	defer sseClient.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go publishEvery(ctx, source, 10*time.Millisecond, sse.Event{Source: "eu", Data: []byte(`{"tweet":{"likes":1}}`)})
+	go publishEvery(ctx, source, 10*time.Millisecond, sse.Event{Source: "us", Data: []byte(`{"tweet":{"likes":2}}`)})
 
 	repo := postStatsRepository{
-		sseClient: sseClient,
+		bus:      startBus(source),
+		registry: testRegistry(t),
 	}
 
-	posts, err := repo.ReadFor(4 * time.Second)
+	posts, err := repo.ReadFor(context.Background(), Query{Duration: 200 * time.Millisecond, Source: "eu"})
 	if err != nil {
 		t.Fatalf("unexpected error, got %v", err)
 	}
 
-	if listenErr != nil {
-		t.Fatalf("unexpected error from sseClient.Listen, got %v", err)
+	if len(posts.Posts) == 0 {
+		t.Fatalf("post stats should not be empty")
 	}
 
-	if len(posts) == 0 {
-		t.Errorf("post stats should not be empty")
+	for _, post := range posts.Posts {
+		if likes, _ := post.value("likes"); likes != 1 {
+			t.Fatalf("expected the posts of the eu stream only, got %v", post)
+		}
 	}
 }
 
 func TestPostStatsRepositoryReadForInvalidEvent(t *testing.T) {
-	server := createSSEServerMock(1*time.Second, []byte("data: invalid"))
-	defer server.Close()
+	type testData struct {
+		name                  string
+		strictDecoding        bool
+		shouldFail            bool
+		expectedDecodeErrors  bool
+		expectedSkippedEvents bool
+	}
 
-	sseClientConfig := sse.Config{
-		ServerURL:               server.URL,
-		MaxReconnectionAttempts: 1,
+	testCases := [...]testData{
+		{
+			name:                  "Success case: invalid events are skipped and counted",
+			expectedDecodeErrors:  true,
+			expectedSkippedEvents: true,
+		},
+		{
+			name:           "Fail case: strict decoding",
+			strictDecoding: true,
+			shouldFail:     true,
+		},
 	}
 
-	sseClient := sse.NewSSEClient(sseClientConfig, loggerInstance)
-	var listenErr error
-	go func() {
-		listenErr = sseClient.Listen()
-	}()
-	defer sseClient.Close()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			source := sources.NewMemory(sse.FeedConfig{}, loggerInstance)
+			defer source.Close()
 
-	repo := postStatsRepository{
-		sseClient: sseClient,
-	}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go publishEvery(ctx, source, 10*time.Millisecond, sse.Event{Data: []byte(eventData)})
+			go publishEvery(ctx, source, 10*time.Millisecond, sse.Event{Data: []byte("invalid")})
+			go publishEvery(ctx, source, 10*time.Millisecond, sse.Event{Data: []byte(`{"tweet":{},"pin":{}}`)})
+
+			repo := postStatsRepository{
+				bus:            startBus(source),
+				strictDecoding: testCase.strictDecoding,
+				registry:       testRegistry(t),
+			}
+
+			window, err := repo.ReadFor(context.Background(), Query{Duration: 200 * time.Millisecond})
+			if testCase.shouldFail {
+				if err == nil {
+					t.Fatalf("expected a decoding error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error, got %v", err)
+			}
+
+			if len(window.Posts) == 0 {
+				t.Errorf("valid posts should be kept")
+			}
+
+			if (window.DecodeErrors > 0) != testCase.expectedDecodeErrors {
+				t.Errorf("unexpected decode errors count %d", window.DecodeErrors)
+			}
 
-	posts, err := repo.ReadFor(4 * time.Second)
-	if err == nil {
-		t.Fatalf("expected error %v, got %v", ErrClosedSubscriber, err)
+			if (window.SkippedEvents > 0) != testCase.expectedSkippedEvents {
+				t.Errorf("unexpected skipped events count %d", window.SkippedEvents)
+			}
+		})
 	}
+}
 
-	if listenErr != nil {
-		t.Fatalf("unexpected error from sseClient.Listen, got %v", err)
+func TestPostStatsRepositoryReadForClosedSource(t *testing.T) {
+	source := sources.NewMemory(sse.FeedConfig{}, loggerInstance)
+
+	repo := postStatsRepository{
+		bus:      startBus(source),
+		registry: testRegistry(t),
 	}
 
-	if len(posts) != 0 {
-		t.Errorf("post stats should be empty")
+	source.Close()
+
+	if _, err := repo.ReadFor(context.Background(), Query{Duration: time.Second}); err == nil {
+		t.Errorf("expected an error once the source is closed, got nil")
 	}
 }
 
-func TestPostStatsRepositoryDecodeEvent(t *testing.T) {
+func TestNewPostStats(t *testing.T) {
 	type testData struct {
-		name           string
-		event          []byte
-		shouldFail     bool
-		expectedResult *postStats
+		name            string
+		dimensions      []Dimension
+		expectedMetrics map[string]float64
+	}
+
+	post := &posts.Post{
+		Platform:  "youtube_video",
+		Timestamp: 1,
+		Metrics:   map[string]int64{"likes": 2, "views": 3, "comments": 0},
 	}
 
 	testCases := [...]testData{
 		{
-			name:       "Success case",
-			event:      []byte(`{"yt":{"likes":2,"timestamp":1}}`),
-			shouldFail: false,
-			expectedResult: &postStats{
-				Likes:     2,
-				Comments:  0,
-				Favorites: 0,
-				Retweets:  0,
-				Timestamp: 1,
+			name:            "Success case: default dimensions",
+			dimensions:      DefaultDimensions,
+			expectedMetrics: map[string]float64{"likes": 2, "comments": 0},
+		},
+		{
+			name: "Success case: dimension with another field name",
+			dimensions: []Dimension{
+				{Name: "plays", Field: "views"},
 			},
+			expectedMetrics: map[string]float64{"plays": 3},
 		},
 		{
-			name:       "Fail case: event is not a json string",
-			event:      []byte(`invalid`),
-			shouldFail: true,
+			name: "Success case: dimension supplied by the platform",
+			dimensions: []Dimension{
+				{Name: "views", Platforms: []string{"youtube_video", "tiktok_video"}},
+			},
+			expectedMetrics: map[string]float64{"views": 3},
 		},
 		{
-			name:       "Fail case: multiple key",
-			event:      []byte(`{"a":{"likes":2,"timestamp":1},"b":{"likes":2,"timestamp":1}}`),
-			shouldFail: true,
+			name: "Success case: dimension not supplied by the platform",
+			dimensions: []Dimension{
+				{Name: "likes", Platforms: []string{"instagram_media"}},
+			},
+			expectedMetrics: map[string]float64{},
 		},
 		{
-			name:       "Fail case: invalid envent body",
-			event:      []byte(`{"yt":{"likes":true}}`),
-			shouldFail: true,
+			name: "Success case: derived dimensions",
+			dimensions: []Dimension{
+				{Name: "likes"},
+				{Name: "views"},
+				{Name: "comments"},
+				{Name: "shares"},
+				{Name: "like_rate", Expression: "likes / views"},
+				{Name: "comment_to_like_ratio", Expression: "comments / likes"},
+				{Name: "like_to_comment_ratio", Expression: "likes / comments"},
+				{Name: "engagement", Expression: "likes + comments + coalesce(shares, 0)"},
+				{Name: "shares_and_likes", Expression: "shares + likes"},
+			},
+			expectedMetrics: map[string]float64{
+				"likes":                 2,
+				"views":                 3,
+				"comments":              0,
+				"like_rate":             2. / 3,
+				"comment_to_like_ratio": 0,
+				"engagement":            2,
+			},
 		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			instance := &postStatsRepository{}
-			post, err := instance.decodeEvent(testCase.event)
+			registry, err := NewRegistry(testCase.dimensions)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 
-			if testCase.shouldFail {
-				if err == nil {
-					t.Fatalf("expected error, got nil")
-				}
-			} else {
-				if err != nil {
-					t.Fatalf("unexpected error")
-				}
+			result := newPostStats(post, registry)
 
-				if *post != *testCase.expectedResult {
-					t.Errorf("expected %v got %v", post, testCase.expectedResult)
-				}
+			if !maps.Equal(result.Metrics, testCase.expectedMetrics) {
+				t.Errorf("expected metrics %v, got %v", testCase.expectedMetrics, result.Metrics)
+			}
+
+			if result.Platform != "youtube_video" || result.Timestamp != 1 {
+				t.Errorf("expected the platform and the timestamp of the post, got %v", result)
 			}
 		})
 	}
 }
+
+func TestPostStatsRepositoryReadForStalledStream(t *testing.T) {
+	source := sources.NewMemory(sse.FeedConfig{}, loggerInstance)
+	defer source.Close()
+
+	source.SetState(sse.StateStalled)
+
+	repo := postStatsRepository{
+		bus:      startBus(source),
+		registry: testRegistry(t),
+	}
+
+	_, err := repo.ReadFor(context.Background(), Query{Duration: 100 * time.Millisecond})
+	if !errors.Is(err, ErrStreamUnavailable) {
+		t.Fatalf("expected error %v, got %v", ErrStreamUnavailable, err)
+	}
+}
+
+func TestPostStatsRepositoryReadForLookback(t *testing.T) {
+	source := sources.NewMemory(sse.FeedConfig{Replay: sse.ReplayConfig{MaxEvents: 100}}, loggerInstance)
+	defer source.Close()
+	created := time.Now()
+
+	repo := postStatsRepository{
+		bus:      startBus(source),
+		registry: testRegistry(t),
+	}
+
+	// The history of the source starts with it, the lookback must not reach further.
+	lookback := time.Millisecond
+	for time.Since(created) <= 2*lookback {
+		runtime.Gosched()
+	}
+
+	// The events are stamped within the lookback, whatever the time the test takes.
+	for range 3 {
+		source.Publish(sse.Event{Name: sse.DefaultEventName, Data: []byte(eventData), ReceivedAt: created.Add(time.Minute)})
+	}
+
+	posts, err := repo.ReadFor(context.Background(), Query{Lookback: lookback})
+	if err != nil {
+		t.Fatalf("unexpected error, got %v", err)
+	}
+
+	if len(posts.Posts) != 3 {
+		t.Errorf("expected the 3 replayed posts, got %d", len(posts.Posts))
+	}
+
+	if _, err := repo.ReadFor(context.Background(), Query{Lookback: time.Hour}); !errors.Is(err, ErrLookbackUnavailable) {
+		t.Errorf("expected error %v, got %v", ErrLookbackUnavailable, err)
+	}
+}
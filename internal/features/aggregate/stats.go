@@ -0,0 +1,151 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+)
+
+// DimensionStats describes the distribution of a dimension over the posts of a window.
+type DimensionStats struct {
+	Count  int     `json:"count"`
+	Sum    Sum     `json:"sum"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+	P95    float64 `json:"p95"`
+	P99    float64 `json:"p99"`
+	StdDev float64 `json:"stddev"`
+}
+
+// Sum is the sum of the values of a dimension. The sum of a raw dimension is an exact int64, not to overflow
+// on large windows, while the sum of a derived dimension, which may be fractional, is a float64.
+type Sum struct {
+	Integer int64
+	Float   float64
+
+	// Derived reports whether the sum is the Float one.
+	Derived bool
+}
+
+// newSum returns the sum of values, the ones of a derived dimension if derived.
+func newSum(values []float64, derived bool) Sum {
+	if derived {
+		total := 0.
+		for _, value := range values {
+			total += value
+		}
+
+		return Sum{Float: total, Derived: true}
+	}
+
+	// The values of the raw dimensions are the integers of the posts.
+	var total int64
+	for _, value := range values {
+		total += int64(value)
+	}
+
+	return Sum{Integer: total}
+}
+
+// Value returns the sum as a floating-point number.
+func (s Sum) Value() float64 {
+	if s.Derived {
+		return s.Float
+	}
+
+	return float64(s.Integer)
+}
+
+// MarshalJSON encodes the sum as a JSON number.
+func (s Sum) MarshalJSON() ([]byte, error) {
+	if s.Derived {
+		return json.Marshal(s.Float)
+	}
+
+	return json.Marshal(s.Integer)
+}
+
+// UnmarshalJSON decodes a JSON number, an integer being the sum of a raw dimension.
+func (s *Sum) UnmarshalJSON(data []byte) error {
+	var integer int64
+	if err := json.Unmarshal(data, &integer); err == nil {
+		*s = Sum{Integer: integer}
+		return nil
+	}
+
+	*s = Sum{Derived: true}
+
+	return json.Unmarshal(data, &s.Float)
+}
+
+// computeStats returns the distribution of values, the ones of a derived dimension if derived. The percentiles
+// are linearly interpolated between the closest ranks and the standard deviation is the population one.
+func computeStats(values []float64, derived bool) DimensionStats {
+	if len(values) == 0 {
+		return DimensionStats{}
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	total := newSum(sorted, derived)
+	mean := total.Value() / float64(len(sorted))
+
+	variance := 0.
+	for _, value := range sorted {
+		variance += (value - mean) * (value - mean)
+	}
+	variance /= float64(len(sorted))
+
+	return DimensionStats{
+		Count:  len(sorted),
+		Sum:    total,
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		Mean:   mean,
+		Median: percentile(sorted, 50),
+		P90:    percentile(sorted, 90),
+		P95:    percentile(sorted, 95),
+		P99:    percentile(sorted, 99),
+		StdDev: math.Sqrt(variance),
+	}
+}
+
+// round returns the stats with their floating-point values rounded to precision decimals.
+func (s DimensionStats) round(precision int) DimensionStats {
+	if s.Sum.Derived {
+		s.Sum.Float = round(s.Sum.Float, precision)
+	}
+
+	s.Min = round(s.Min, precision)
+	s.Max = round(s.Max, precision)
+	s.Mean = round(s.Mean, precision)
+	s.Median = round(s.Median, precision)
+	s.P90 = round(s.P90, precision)
+	s.P95 = round(s.P95, precision)
+	s.P99 = round(s.P99, precision)
+	s.StdDev = round(s.StdDev, precision)
+
+	return s
+}
+
+// round rounds value half away from zero to precision decimals.
+func round(value float64, precision int) float64 {
+	scale := math.Pow(10, float64(precision))
+
+	return math.Round(value*scale) / scale
+}
+
+// percentile returns the p-th percentile of the sorted values, which must not be empty.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	weight := rank - float64(lower)
+
+	return sorted[lower] + weight*(sorted[upper]-sorted[lower])
+}
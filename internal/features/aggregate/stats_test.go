@@ -0,0 +1,162 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	type testData struct {
+		name           string
+		values         []float64
+		derived        bool
+		expectedResult DimensionStats
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: no values",
+			values:         []float64{},
+			expectedResult: DimensionStats{},
+		},
+		{
+			name:   "Success case: single value",
+			values: []float64{7},
+			expectedResult: DimensionStats{
+				Count:  1,
+				Sum:    Sum{Integer: 7},
+				Min:    7,
+				Max:    7,
+				Mean:   7,
+				Median: 7,
+				P90:    7,
+				P95:    7,
+				P99:    7,
+				StdDev: 0,
+			},
+		},
+		{
+			name:   "Success case: unsorted values",
+			values: []float64{4, 1, 3, 2},
+			expectedResult: DimensionStats{
+				Count:  4,
+				Sum:    Sum{Integer: 10},
+				Min:    1,
+				Max:    4,
+				Mean:   2.5,
+				Median: 2.5,
+				P90:    3.7,
+				P95:    3.85,
+				P99:    3.97,
+				StdDev: math.Sqrt(1.25),
+			},
+		},
+		{
+			name:   "Success case: skewed values",
+			values: []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 1000},
+			expectedResult: DimensionStats{
+				Count:  10,
+				Sum:    Sum{Integer: 1000},
+				Min:    0,
+				Max:    1000,
+				Mean:   100,
+				Median: 0,
+				P90:    100,
+				P95:    550,
+				P99:    910,
+				StdDev: 300,
+			},
+		},
+		{
+			name:    "Success case: derived values",
+			values:  []float64{0.5, 0.25, 0.75},
+			derived: true,
+			expectedResult: DimensionStats{
+				Count:  3,
+				Sum:    Sum{Float: 1.5, Derived: true},
+				Min:    0.25,
+				Max:    0.75,
+				Mean:   0.5,
+				Median: 0.5,
+				P90:    0.7,
+				P95:    0.725,
+				P99:    0.745,
+				StdDev: math.Sqrt(0.125 / 3),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			stats := computeStats(testCase.values, testCase.derived)
+			if !equalDimensionStats(stats, testCase.expectedResult) {
+				t.Errorf("expected %+v, got %+v", testCase.expectedResult, stats)
+			}
+		})
+	}
+}
+
+func TestSumJSON(t *testing.T) {
+	type testData struct {
+		name         string
+		sum          Sum
+		expectedJSON string
+	}
+
+	testCases := [...]testData{
+		{
+			name:         "Success case: raw dimension beyond float64 precision",
+			sum:          Sum{Integer: math.MaxInt64},
+			expectedJSON: "9223372036854775807",
+		},
+		{
+			name:         "Success case: derived dimension",
+			sum:          Sum{Float: 1.5, Derived: true},
+			expectedJSON: "1.5",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			data, err := json.Marshal(testCase.sum)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(data) != testCase.expectedJSON {
+				t.Errorf("expected %s, got %s", testCase.expectedJSON, data)
+			}
+
+			var sum Sum
+			if err := json.Unmarshal(data, &sum); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if sum != testCase.sum {
+				t.Errorf("expected %+v, got %+v", testCase.sum, sum)
+			}
+		})
+	}
+}
+
+func TestNewSum(t *testing.T) {
+	// 2^53 + 1 can't be represented by a float64, the sum of the raw dimensions must stay exact.
+	values := []float64{1 << 53, 1, 1}
+
+	if sum := newSum(values, false); sum.Integer != 1<<53+2 || sum.Derived {
+		t.Errorf("expected an exact integer sum, got %+v", sum)
+	}
+}
+
+func equalDimensionStats(a, b DimensionStats) bool {
+	const epsilon = 1e-9
+
+	return a.Count == b.Count && a.Sum == b.Sum && a.Min == b.Min && a.Max == b.Max &&
+		math.Abs(a.Mean-b.Mean) < epsilon &&
+		math.Abs(a.Median-b.Median) < epsilon &&
+		math.Abs(a.P90-b.P90) < epsilon &&
+		math.Abs(a.P95-b.P95) < epsilon &&
+		math.Abs(a.P99-b.P99) < epsilon &&
+		math.Abs(a.StdDev-b.StdDev) < epsilon
+}
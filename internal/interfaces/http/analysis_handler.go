@@ -1,8 +1,12 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/features/aggregate"
@@ -10,26 +14,84 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// allDimensions requests every authorized dimension.
+	allDimensions = "all"
+
+	// maxPrecision is the maximum number of decimals of the rounded averages.
+	maxPrecision = 10
+
+	// excludedPlatformPrefix marks a platform to leave out in the platform query parameter.
+	excludedPlatformPrefix = "-"
+)
+
+var (
+	errUnauthorizedDimension = errors.New("unauthorized dimension")
+	errUnavailableDimension  = errors.New("dimension not carried by the posts of the requested platforms")
+	errInvalidPlatform       = errors.New("invalid platform")
+)
+
+// StreamSources lists the streams the events come from.
+type StreamSources interface {
+	// Sources returns the names of the streams, nil if they can't be known in advance.
+	Sources() []string
+}
+
 type AnalysisHandlerConfig struct {
+	// AuthorizedDimensions restricts the queryable dimensions to these ones of the dimension registry,
+	// all of them if empty.
 	AuthorizedDimensions []string `json:"authorized_dimensions"`
+
+	// IntegerAverages returns truncated integer averages by default, as they used to be,
+	// for the clients relying on this contract. The integer_averages query parameter overrides it.
+	IntegerAverages bool `json:"integer_averages"`
 }
 
 type AnalysisHandler struct {
 	aggregateFeatures   aggregate.AggregateFeatures
+	registry            *aggregate.Registry
 	authorizedDimension []string
-	log                 *logs.Logger
+	integerAverages     bool
+
+	// sources are the names of the streams the source query parameter can select, any if nil.
+	sources []string
+
+	log *logs.Logger
 }
 
-func NewAnalysisHandler(config AnalysisHandlerConfig, aggregateFeatures aggregate.AggregateFeatures, log *logs.Logger) *AnalysisHandler {
+// NewAnalysisHandler creates the handler of the analysis of the dimensions of registry, over the posts
+// of the streams of sources. An error wrapping errUnauthorizedDimension is returned if an authorized
+// dimension isn't part of the registry.
+func NewAnalysisHandler(config AnalysisHandlerConfig, aggregateFeatures aggregate.AggregateFeatures, registry *aggregate.Registry, sources StreamSources, log *logs.Logger) (*AnalysisHandler, error) {
+	authorizedDimension, err := queryableDimensions(registry, config.AuthorizedDimensions)
+	if err != nil {
+		return nil, err
+	}
+
 	return &AnalysisHandler{
 		aggregateFeatures:   aggregateFeatures,
-		authorizedDimension: config.AuthorizedDimensions,
+		registry:            registry,
+		authorizedDimension: authorizedDimension,
+		integerAverages:     config.IntegerAverages,
+		sources:             sources.Sources(),
 		log:                 log,
-	}
+	}, nil
 }
 
 func (h *AnalysisHandler) RegisterRoutes(router *gin.Engine) {
 	router.GET("/analysis", h.Get)
+	router.GET("/analysis/dimensions", h.GetDimensions)
+}
+
+// GetDimensions lists the queryable dimensions, as described by the dimension registry.
+func (h *AnalysisHandler) GetDimensions(c *gin.Context) {
+	dimensions := make([]aggregate.Dimension, 0, len(h.authorizedDimension))
+	for _, name := range h.authorizedDimension {
+		dimension, _ := h.registry.Lookup(name)
+		dimensions = append(dimensions, dimension)
+	}
+
+	c.JSON(http.StatusOK, dimensions)
 }
 
 func (h *AnalysisHandler) Get(c *gin.Context) {
@@ -52,24 +114,262 @@ func (h *AnalysisHandler) Get(c *gin.Context) {
 		return
 	}
 
-	dimension, ok := c.GetQuery("dimension")
+	lookback := time.Duration(0)
+	if rawLookback, ok := c.GetQuery("lookback"); ok {
+		lookback, err = time.ParseDuration(rawLookback)
+		if err != nil {
+			h.log.Error("AnalysisHandler.Get error: can't parse lookback", logs.Field{Key: "error", Value: err.Error()})
+			c.JSON(http.StatusBadRequest, "Query parameter lookback is not in the go time duration format")
+			return
+		}
+
+		if lookback < 0 {
+			h.log.Error("AnalysisHandler.Get error: negative lookback", logs.Field{Key: "lookback", Value: rawLookback})
+			c.JSON(http.StatusBadRequest, "Query parameter lookback must be a positive value")
+			return
+		}
+	}
+
+	rawDimensions, ok := c.GetQueryArray("dimension")
 	if !ok {
 		c.JSON(http.StatusBadRequest, "Query parameter dimension is missing")
 		return
 	}
 
-	if !slices.Contains(h.authorizedDimension, dimension) {
-		h.log.Error("AnalysisHandler.Get error: unauthorized dimension", logs.Field{Key: "supplied_dimension", Value: dimension})
+	platforms, err := h.requestedPlatforms(c.QueryArray("platform"))
+	if err != nil {
+		h.log.Error("AnalysisHandler.Get error: invalid platform", logs.Field{Key: "error", Value: err.Error()})
+		c.JSON(http.StatusBadRequest, "Query parameter platform is invalid: "+err.Error())
+		return
+	}
+
+	dimensions, err := h.requestedDimensions(rawDimensions, platforms)
+	if errors.Is(err, errUnavailableDimension) {
+		h.log.Error("AnalysisHandler.Get error: unavailable dimension", logs.Field{Key: "error", Value: err.Error()})
+		c.JSON(http.StatusBadRequest, "Query parameter dimension is invalid: "+err.Error())
+		return
+	}
+
+	if err != nil {
+		h.log.Error("AnalysisHandler.Get error: unauthorized dimension", logs.Field{Key: "error", Value: err.Error()})
 		c.JSON(http.StatusBadRequest, "Unauthorized dimension")
 		return
 	}
 
-	aggregate, err := h.aggregateFeatures.Aggregate(duration, dimension)
+	stats, err := boolQuery(c, "stats")
+	if err != nil {
+		h.log.Error("AnalysisHandler.Get error: can't parse stats", logs.Field{Key: "error", Value: err.Error()})
+		c.JSON(http.StatusBadRequest, "Query parameter stats must be a boolean")
+		return
+	}
+
+	includeAbsent, err := boolQuery(c, "include_absent")
+	if err != nil {
+		h.log.Error("AnalysisHandler.Get error: can't parse include_absent", logs.Field{Key: "error", Value: err.Error()})
+		c.JSON(http.StatusBadRequest, "Query parameter include_absent must be a boolean")
+		return
+	}
+
+	var precision *int
+	if rawPrecision, ok := c.GetQuery("precision"); ok {
+		value, err := strconv.Atoi(rawPrecision)
+		if err != nil || value < 0 || value > maxPrecision {
+			h.log.Error("AnalysisHandler.Get error: invalid precision", logs.Field{Key: "precision", Value: rawPrecision})
+			c.JSON(http.StatusBadRequest, fmt.Sprintf("Query parameter precision must be an integer between 0 and %d", maxPrecision))
+			return
+		}
+
+		precision = &value
+	}
+
+	integerAverages := h.integerAverages
+	if _, ok := c.GetQuery("integer_averages"); ok {
+		integerAverages, err = boolQuery(c, "integer_averages")
+		if err != nil {
+			h.log.Error("AnalysisHandler.Get error: can't parse integer_averages", logs.Field{Key: "error", Value: err.Error()})
+			c.JSON(http.StatusBadRequest, "Query parameter integer_averages must be a boolean")
+			return
+		}
+	}
+
+	groupBy, ok := c.GetQuery("group_by")
+	if ok && groupBy != aggregate.GroupByPlatform {
+		h.log.Error("AnalysisHandler.Get error: unknown group", logs.Field{Key: "group_by", Value: groupBy})
+		c.JSON(http.StatusBadRequest, "Query parameter group_by must be platform")
+		return
+	}
+
+	source, ok := c.GetQuery("source")
+	if ok && source == "" {
+		c.JSON(http.StatusBadRequest, "Query parameter source must not be empty")
+		return
+	}
+
+	if ok && h.sources != nil && !slices.Contains(h.sources, source) {
+		h.log.Error("AnalysisHandler.Get error: unknown source", logs.Field{Key: "source", Value: source})
+		c.JSON(http.StatusBadRequest, fmt.Sprintf("Query parameter source must be one of the streams %v", h.sources))
+		return
+	}
+
+	aggregation, err := h.aggregateFeatures.Aggregate(c.Request.Context(), aggregate.Query{
+		Duration:        duration,
+		Lookback:        lookback,
+		Dimensions:      dimensions,
+		Stats:           stats,
+		IncludeAbsent:   includeAbsent,
+		Precision:       precision,
+		IntegerAverages: integerAverages,
+		Platforms:       platforms,
+		GroupBy:         groupBy,
+		Source:          source,
+	})
 	if err != nil {
 		h.log.Error("AnalysisHandler.Get error: ", logs.Field{Key: "error", Value: err.Error()})
+
+		if errors.Is(err, aggregate.ErrStreamUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, "The upstream stream is not available")
+			return
+		}
+
+		if errors.Is(err, aggregate.ErrLookbackUnavailable) {
+			c.JSON(http.StatusBadRequest, "The lookback exceeds the available stream history")
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, "The server is not able to perform the request")
 		return
 	}
 
-	c.JSON(http.StatusOK, aggregate)
+	c.JSON(http.StatusOK, aggregation)
+}
+
+// boolQuery returns the boolean value of the name query parameter, false if it is missing.
+func boolQuery(c *gin.Context, name string) (bool, error) {
+	raw, ok := c.GetQuery(name)
+	if !ok {
+		return false, nil
+	}
+
+	return strconv.ParseBool(raw)
+}
+
+// requestedDimensions returns the dimensions of the repeated and comma-separated values, without duplicates.
+// The "all" value stands for every authorized dimension carried by the posts of platforms. A dimension these
+// posts never carry is rejected with errUnavailableDimension.
+func (h *AnalysisHandler) requestedDimensions(values []string, platforms aggregate.PlatformFilter) ([]string, error) {
+	dimensions := []string{}
+
+	for _, value := range splitValues(values) {
+		requested := []string{value}
+		if value == allDimensions {
+			requested = slices.DeleteFunc(slices.Clone(h.authorizedDimension), func(dimension string) bool {
+				return !h.carried(dimension, platforms)
+			})
+
+			if len(requested) == 0 {
+				return nil, fmt.Errorf("%w: %q on %s", errUnavailableDimension, value, formatPlatforms(platforms))
+			}
+		} else if !slices.Contains(h.authorizedDimension, value) {
+			return nil, fmt.Errorf("%w: %q", errUnauthorizedDimension, value)
+		} else if !h.carried(value, platforms) {
+			return nil, fmt.Errorf("%w: %q on %s", errUnavailableDimension, value, formatPlatforms(platforms))
+		}
+
+		for _, dimension := range requested {
+			if !slices.Contains(dimensions, dimension) {
+				dimensions = append(dimensions, dimension)
+			}
+		}
+	}
+
+	if len(dimensions) == 0 {
+		return nil, errUnauthorizedDimension
+	}
+
+	return dimensions, nil
+}
+
+// requestedPlatforms returns the platform filter of the repeated and comma-separated values.
+// A platform prefixed with "-" is excluded, the other ones are included.
+func (h *AnalysisHandler) requestedPlatforms(values []string) (aggregate.PlatformFilter, error) {
+	filter := aggregate.PlatformFilter{}
+
+	for _, value := range splitValues(values) {
+		platform, excluded := strings.CutPrefix(value, excludedPlatformPrefix)
+		if platform == "" {
+			return aggregate.PlatformFilter{}, fmt.Errorf("%w: %q", errInvalidPlatform, value)
+		}
+
+		if excluded {
+			filter.Exclude = append(filter.Exclude, platform)
+		} else {
+			filter.Include = append(filter.Include, platform)
+		}
+	}
+
+	return filter, nil
+}
+
+// formatPlatforms returns platforms as written in the platform query parameter.
+func formatPlatforms(platforms aggregate.PlatformFilter) string {
+	values := slices.Clone(platforms.Include)
+	for _, platform := range platforms.Exclude {
+		values = append(values, excludedPlatformPrefix+platform)
+	}
+
+	return strings.Join(values, ",")
+}
+
+// carried reports whether dimension can be carried by the posts passing platforms, according to the platforms
+// supplying it in the registry. Without included platforms, the stream may hold posts of any platform, so only
+// a dimension supplied by excluded platforms only can't be carried.
+func (h *AnalysisHandler) carried(name string, platforms aggregate.PlatformFilter) bool {
+	dimension, ok := h.registry.Lookup(name)
+	if !ok {
+		return false
+	}
+
+	if len(platforms.Include) == 0 {
+		return len(dimension.Platforms) == 0 || slices.ContainsFunc(dimension.Platforms, platforms.Match)
+	}
+
+	for _, platform := range platforms.Include {
+		if platforms.Match(platform) && dimension.SuppliedBy(platform) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// queryableDimensions returns the dimensions of registry among authorized, all of them if authorized is empty,
+// in the registry order. An error is returned if an authorized dimension isn't part of the registry.
+func queryableDimensions(registry *aggregate.Registry, authorized []string) ([]string, error) {
+	for _, dimension := range authorized {
+		if _, ok := registry.Lookup(dimension); !ok {
+			return nil, fmt.Errorf("%w: %q is not a registered dimension", errUnauthorizedDimension, dimension)
+		}
+	}
+
+	names := registry.Names()
+	if len(authorized) == 0 {
+		return names, nil
+	}
+
+	return slices.DeleteFunc(names, func(name string) bool {
+		return !slices.Contains(authorized, name)
+	}), nil
+}
+
+// splitValues returns the trimmed comma-separated items of the repeated query parameter values.
+func splitValues(values []string) []string {
+	items := []string{}
+
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+	}
+
+	return items
 }
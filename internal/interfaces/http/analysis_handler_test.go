@@ -2,11 +2,14 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/features/aggregate"
@@ -25,12 +28,22 @@ var (
 	loggerInstance, _ = logs.NewLogger(logs.Config{
 		Level: "INFO",
 	})
+
+	testRegistry, _ = aggregate.NewRegistry([]aggregate.Dimension{
+		{Name: "comments", Platforms: []string{"tweet", "instagram_media", "article"}},
+		{Name: "likes", Platforms: []string{"instagram_media"}},
+		{Name: "favorites", Platforms: []string{"tweet"}},
+		{Name: "retweets", Platforms: []string{"tweet"}},
+	})
 )
 
 func TestNewAnalysisHandler(t *testing.T) {
 	feature := mockings.AggregateFeatureMocking{}
 
-	handler := NewAnalysisHandler(testConfig, &feature, loggerInstance)
+	handler, err := NewAnalysisHandler(testConfig, &feature, testRegistry, &mockings.StreamSourcesMocking{Names: []string{"eu"}}, loggerInstance)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	if !slices.Equal(testConfig.AuthorizedDimensions, handler.authorizedDimension) {
 		t.Errorf("AnalysisHandler authorized dimensions differ from the injected ones.")
@@ -39,12 +52,67 @@ func TestNewAnalysisHandler(t *testing.T) {
 	if handler.aggregateFeatures != &feature {
 		t.Errorf("AnalysisHandler aggregate feature differ from the injected one.")
 	}
+
+	if handler.registry != testRegistry {
+		t.Errorf("AnalysisHandler registry differ from the injected one.")
+	}
+
+	if !slices.Equal(handler.sources, []string{"eu"}) {
+		t.Errorf("AnalysisHandler sources differ from the injected ones.")
+	}
+}
+
+func TestQueryableDimensions(t *testing.T) {
+	type testData struct {
+		name           string
+		authorized     []string
+		shouldFail     bool
+		expectedResult []string
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: every registered dimension by default",
+			authorized:     nil,
+			expectedResult: []string{"comments", "likes", "favorites", "retweets"},
+		},
+		{
+			name:           "Success case: authorized dimensions in registry order",
+			authorized:     []string{"retweets", "likes"},
+			expectedResult: []string{"likes", "retweets"},
+		},
+		{
+			name:       "Fail case: unregistered dimension",
+			authorized: []string{"likes", "shares"},
+			shouldFail: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dimensions, err := queryableDimensions(testRegistry, testCase.authorized)
+			if testCase.shouldFail {
+				if !errors.Is(err, errUnauthorizedDimension) {
+					t.Errorf("expected error %v, got %v", errUnauthorizedDimension, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(dimensions, testCase.expectedResult) {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, dimensions)
+			}
+		})
+	}
 }
 
 func TestAnalysisHandlerRegisterRoutes(t *testing.T) {
 	router := gin.Default()
 
-	handler := NewAnalysisHandler(testConfig, &mockings.AggregateFeatureMocking{}, loggerInstance)
+	handler, _ := NewAnalysisHandler(testConfig, &mockings.AggregateFeatureMocking{}, testRegistry, &mockings.StreamSourcesMocking{}, loggerInstance)
 
 	handler.RegisterRoutes(router)
 
@@ -111,6 +179,84 @@ func TestAnalysisHandlerGet(t *testing.T) {
 			expectedStatusCode: http.StatusBadRequest,
 			hasResponseBody:    false,
 		},
+		{
+			name: "Success case: lookback",
+			queryParams: map[string]string{
+				"duration":  "0s",
+				"lookback":  "1m",
+				"dimension": "likes",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusOK,
+			hasResponseBody:    true,
+		},
+		{
+			name: "Fail case: lookback query param is not a go duration",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"lookback":  "invalid",
+				"dimension": "likes",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Fail case: negative lookback",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"lookback":  "-1m",
+				"dimension": "likes",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Success case: source",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"source":    "eu",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusOK,
+			hasResponseBody:    true,
+		},
+		{
+			name: "Fail case: unknown source",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"source":    "ue",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Fail case: empty source",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"source":    "",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
 		{
 			name: "Fail case: no dimension query param",
 			queryParams: map[string]string{
@@ -134,6 +280,235 @@ func TestAnalysisHandlerGet(t *testing.T) {
 			expectedStatusCode: http.StatusBadRequest,
 			hasResponseBody:    false,
 		},
+		{
+			name: "Success case: comma-separated dimensions",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes, comments",
+			},
+			authorizedDimension: []string{
+				"likes",
+				"comments",
+			},
+			expectedStatusCode: http.StatusOK,
+			hasResponseBody:    true,
+		},
+		{
+			name: "Success case: all dimensions",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "all",
+			},
+			authorizedDimension: []string{
+				"likes",
+				"comments",
+			},
+			expectedStatusCode: http.StatusOK,
+			hasResponseBody:    true,
+		},
+		{
+			name: "Fail case: one unauthorized dimension in the list",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes,unknown",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Fail case: empty dimension",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Fail case: all dimensions without authorized dimension",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "all",
+			},
+			authorizedDimension: []string{},
+			expectedStatusCode:  http.StatusBadRequest,
+			hasResponseBody:     false,
+		},
+		{
+			name: "Success case: stats",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"stats":     "true",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusOK,
+			hasResponseBody:    true,
+		},
+		{
+			name: "Success case: include absent",
+			queryParams: map[string]string{
+				"duration":       "5s",
+				"dimension":      "likes",
+				"include_absent": "1",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusOK,
+			hasResponseBody:    true,
+		},
+		{
+			name: "Fail case: include absent is not a boolean",
+			queryParams: map[string]string{
+				"duration":       "5s",
+				"dimension":      "likes",
+				"include_absent": "maybe",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Success case: precision",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"precision": "2",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusOK,
+			hasResponseBody:    true,
+		},
+		{
+			name: "Fail case: precision is not an integer",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"precision": "two",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Fail case: negative precision",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"precision": "-1",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Fail case: precision too high",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"precision": "11",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Fail case: integer averages is not a boolean",
+			queryParams: map[string]string{
+				"duration":         "5s",
+				"dimension":        "likes",
+				"integer_averages": "sometimes",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Success case: grouped by platform",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"group_by":  "platform",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusOK,
+			hasResponseBody:    true,
+		},
+		{
+			name: "Fail case: unknown group",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"group_by":  "author",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Success case: platforms",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"platform":  "instagram_media,-tweet",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusOK,
+			hasResponseBody:    true,
+		},
+		{
+			name: "Fail case: empty platform",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"platform":  "",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
+		{
+			name: "Fail case: stats is not a boolean",
+			queryParams: map[string]string{
+				"duration":  "5s",
+				"dimension": "likes",
+				"stats":     "yes please",
+			},
+			authorizedDimension: []string{
+				"likes",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			hasResponseBody:    false,
+		},
 		{
 			name: "Fail case: empty authorized dimensions blocks everything",
 			queryParams: map[string]string{
@@ -162,7 +537,9 @@ func TestAnalysisHandlerGet(t *testing.T) {
 
 			instance := &AnalysisHandler{
 				aggregateFeatures:   &mockings.AggregateFeatureMocking{},
+				registry:            testRegistry,
 				authorizedDimension: testCase.authorizedDimension,
+				sources:             []string{"eu", "us"},
 				log:                 loggerInstance,
 			}
 
@@ -204,6 +581,7 @@ func TestAnalysisHandlerGetAggregateFeatureError(t *testing.T) {
 
 	instance := &AnalysisHandler{
 		aggregateFeatures: &mockings.AggregateFeatureErrorMocking{},
+		registry:          testRegistry,
 		authorizedDimension: []string{
 			"likes",
 		},
@@ -216,3 +594,445 @@ func TestAnalysisHandlerGetAggregateFeatureError(t *testing.T) {
 		t.Fatalf("Expected status code %d, got %d", http.StatusInternalServerError, writer.Code)
 	}
 }
+
+func TestAnalysisHandlerGetStreamUnavailable(t *testing.T) {
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+
+	ctx.Request = httptest.NewRequest("GET", "/analysis", nil)
+
+	values := url.Values{
+		"duration":  []string{"5s"},
+		"dimension": []string{"likes"},
+	}
+
+	ctx.Request.URL.RawQuery = values.Encode()
+
+	instance := &AnalysisHandler{
+		aggregateFeatures: &mockings.AggregateFeatureUnavailableMocking{},
+		registry:          testRegistry,
+		authorizedDimension: []string{
+			"likes",
+		},
+		log: loggerInstance,
+	}
+
+	instance.Get(ctx)
+
+	if writer.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status code %d, got %d", http.StatusServiceUnavailable, writer.Code)
+	}
+}
+
+func TestAnalysisHandlerGetLookbackUnavailable(t *testing.T) {
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+
+	ctx.Request = httptest.NewRequest("GET", "/analysis", nil)
+
+	values := url.Values{
+		"duration":  []string{"5s"},
+		"lookback":  []string{"1h"},
+		"dimension": []string{"likes"},
+	}
+
+	ctx.Request.URL.RawQuery = values.Encode()
+
+	instance := &AnalysisHandler{
+		aggregateFeatures: &mockings.AggregateFeatureLookbackUnavailableMocking{},
+		registry:          testRegistry,
+		authorizedDimension: []string{
+			"likes",
+		},
+		log: loggerInstance,
+	}
+
+	instance.Get(ctx)
+
+	if writer.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status code %d, got %d", http.StatusBadRequest, writer.Code)
+	}
+}
+
+func TestAnalysisHandlerGetRounding(t *testing.T) {
+	type testData struct {
+		name                    string
+		queryParams             map[string]string
+		integerAverages         bool
+		expectedPrecision       *int
+		expectedIntegerAverages bool
+	}
+
+	precision := 2
+
+	testCases := [...]testData{
+		{
+			name:                    "Success case: float averages by default",
+			queryParams:             map[string]string{},
+			expectedPrecision:       nil,
+			expectedIntegerAverages: false,
+		},
+		{
+			name:                    "Success case: precision",
+			queryParams:             map[string]string{"precision": "2"},
+			expectedPrecision:       &precision,
+			expectedIntegerAverages: false,
+		},
+		{
+			name:                    "Success case: integer averages by configuration",
+			queryParams:             map[string]string{},
+			integerAverages:         true,
+			expectedIntegerAverages: true,
+		},
+		{
+			name:                    "Success case: integer averages overridden by the request",
+			queryParams:             map[string]string{"integer_averages": "false"},
+			integerAverages:         true,
+			expectedIntegerAverages: false,
+		},
+		{
+			name:                    "Success case: integer averages requested",
+			queryParams:             map[string]string{"integer_averages": "true"},
+			expectedIntegerAverages: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+
+			ctx.Request = httptest.NewRequest("GET", "/analysis", nil)
+
+			values := url.Values{"duration": {"5s"}, "dimension": {"likes"}}
+			for k, v := range testCase.queryParams {
+				values[k] = []string{v}
+			}
+			ctx.Request.URL.RawQuery = values.Encode()
+
+			feature := &mockings.AggregateFeatureQueryMocking{}
+			instance, err := NewAnalysisHandler(AnalysisHandlerConfig{
+				AuthorizedDimensions: []string{"likes"},
+				IntegerAverages:      testCase.integerAverages,
+			}, feature, testRegistry, &mockings.StreamSourcesMocking{}, loggerInstance)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			instance.Get(ctx)
+
+			if writer.Code != http.StatusOK {
+				t.Fatalf("expected status code %d, got %d", http.StatusOK, writer.Code)
+			}
+
+			if (feature.Query.Precision == nil) != (testCase.expectedPrecision == nil) ||
+				(feature.Query.Precision != nil && *feature.Query.Precision != *testCase.expectedPrecision) {
+				t.Errorf("expected precision %v, got %v", testCase.expectedPrecision, feature.Query.Precision)
+			}
+
+			if feature.Query.IntegerAverages != testCase.expectedIntegerAverages {
+				t.Errorf("expected integer averages %v, got %v", testCase.expectedIntegerAverages, feature.Query.IntegerAverages)
+			}
+		})
+	}
+}
+
+func TestAnalysisHandlerGetUnavailableDimension(t *testing.T) {
+	type testData struct {
+		name     string
+		platform string
+	}
+
+	testCases := [...]testData{
+		{
+			name:     "Fail case: dimension never carried by the included platform",
+			platform: "instagram_media",
+		},
+		{
+			name:     "Fail case: dimension carried by the excluded platform only",
+			platform: "-tweet",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+
+			ctx.Request = httptest.NewRequest("GET", "/analysis", nil)
+			ctx.Request.URL.RawQuery = url.Values{
+				"duration":  {"5s"},
+				"dimension": {"retweets"},
+				"platform":  {testCase.platform},
+			}.Encode()
+
+			instance, err := NewAnalysisHandler(AnalysisHandlerConfig{
+				AuthorizedDimensions: []string{"likes", "retweets"},
+			}, &mockings.AggregateFeatureMocking{}, testRegistry, &mockings.StreamSourcesMocking{}, loggerInstance)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			instance.Get(ctx)
+
+			if writer.Code != http.StatusBadRequest {
+				t.Fatalf("expected status code %d, got %d", http.StatusBadRequest, writer.Code)
+			}
+
+			if body := writer.Body.String(); !strings.Contains(body, "retweets") || !strings.Contains(body, testCase.platform) {
+				t.Errorf("expected the response to name the dimension and the platform, got %s", body)
+			}
+		})
+	}
+}
+
+func TestAnalysisHandlerRequestedDimensions(t *testing.T) {
+	type testData struct {
+		name           string
+		values         []string
+		platforms      aggregate.PlatformFilter
+		shouldFail     bool
+		expectedError  error
+		expectedResult []string
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: repeated dimensions",
+			values:         []string{"likes", "comments"},
+			expectedResult: []string{"likes", "comments"},
+		},
+		{
+			name:           "Success case: repeated and comma-separated dimensions without duplicates",
+			values:         []string{"likes,comments", "comments", "retweets"},
+			expectedResult: []string{"likes", "comments", "retweets"},
+		},
+		{
+			name:           "Success case: all dimensions",
+			values:         []string{"comments,all"},
+			expectedResult: []string{"comments", "likes", "favorites", "retweets"},
+		},
+		{
+			name:           "Success case: dimension carried by one of the platforms",
+			values:         []string{"retweets"},
+			platforms:      aggregate.PlatformFilter{Include: []string{"tweet", "instagram_media"}},
+			expectedResult: []string{"retweets"},
+		},
+		{
+			name:           "Success case: dimension carried by a platform not excluded",
+			values:         []string{"retweets"},
+			platforms:      aggregate.PlatformFilter{Exclude: []string{"instagram_media"}},
+			expectedResult: []string{"retweets"},
+		},
+		{
+			name:           "Success case: all dimensions carried by the platforms not excluded",
+			values:         []string{"all"},
+			platforms:      aggregate.PlatformFilter{Exclude: []string{"tweet"}},
+			expectedResult: []string{"comments", "likes"},
+		},
+		{
+			name:           "Success case: all dimensions carried by the platforms",
+			values:         []string{"all"},
+			platforms:      aggregate.PlatformFilter{Include: []string{"instagram_media"}},
+			expectedResult: []string{"comments", "likes"},
+		},
+		{
+			name:          "Fail case: unauthorized dimension",
+			values:        []string{"likes", "views"},
+			shouldFail:    true,
+			expectedError: errUnauthorizedDimension,
+		},
+		{
+			name:          "Fail case: dimension never carried by the platform",
+			values:        []string{"retweets"},
+			platforms:     aggregate.PlatformFilter{Include: []string{"instagram_media"}},
+			shouldFail:    true,
+			expectedError: errUnavailableDimension,
+		},
+		{
+			name:          "Fail case: dimension carried by an excluded platform only",
+			values:        []string{"likes"},
+			platforms:     aggregate.PlatformFilter{Include: []string{"tweet", "instagram_media"}, Exclude: []string{"instagram_media"}},
+			shouldFail:    true,
+			expectedError: errUnavailableDimension,
+		},
+		{
+			name:          "Fail case: dimension carried by excluded platforms only",
+			values:        []string{"retweets"},
+			platforms:     aggregate.PlatformFilter{Exclude: []string{"tweet"}},
+			shouldFail:    true,
+			expectedError: errUnavailableDimension,
+		},
+		{
+			name:          "Fail case: no dimension carried by the platform",
+			values:        []string{"all"},
+			platforms:     aggregate.PlatformFilter{Include: []string{"pin"}},
+			shouldFail:    true,
+			expectedError: errUnavailableDimension,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := &AnalysisHandler{
+				registry:            testRegistry,
+				authorizedDimension: []string{"comments", "likes", "favorites", "retweets"},
+			}
+
+			dimensions, err := handler.requestedDimensions(testCase.values, testCase.platforms)
+			if testCase.shouldFail {
+				if !errors.Is(err, testCase.expectedError) {
+					t.Errorf("expected error %v, got %v", testCase.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(dimensions, testCase.expectedResult) {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, dimensions)
+			}
+		})
+	}
+}
+
+func TestAnalysisHandlerRequestedPlatforms(t *testing.T) {
+	type testData struct {
+		name           string
+		values         []string
+		shouldFail     bool
+		expectedResult aggregate.PlatformFilter
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: no platform",
+			values:         []string{},
+			expectedResult: aggregate.PlatformFilter{},
+		},
+		{
+			name:   "Success case: included and excluded platforms",
+			values: []string{"tweet, instagram_media", "-article"},
+			expectedResult: aggregate.PlatformFilter{
+				Include: []string{"tweet", "instagram_media"},
+				Exclude: []string{"article"},
+			},
+		},
+		{
+			name:       "Fail case: empty platform",
+			values:     []string{"tweet,"},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: empty excluded platform",
+			values:     []string{"-"},
+			shouldFail: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			handler := &AnalysisHandler{
+				registry: testRegistry,
+			}
+
+			platforms, err := handler.requestedPlatforms(testCase.values)
+			if testCase.shouldFail {
+				if !errors.Is(err, errInvalidPlatform) {
+					t.Errorf("expected error %v, got %v", errInvalidPlatform, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(platforms.Include, testCase.expectedResult.Include) ||
+				!slices.Equal(platforms.Exclude, testCase.expectedResult.Exclude) {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, platforms)
+			}
+		})
+	}
+}
+
+func TestAnalysisHandlerGetAnySource(t *testing.T) {
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/analysis?duration=5s&dimension=likes&source=eu", nil)
+
+	// A recording holds the streams it was captured from, which aren't known in advance.
+	instance, err := NewAnalysisHandler(testConfig, &mockings.AggregateFeatureMocking{}, testRegistry, &mockings.StreamSourcesMocking{}, loggerInstance)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	instance.Get(ctx)
+
+	if writer.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, writer.Code)
+	}
+}
+
+func TestAnalysisHandlerGetDimensions(t *testing.T) {
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+
+	ctx.Request = httptest.NewRequest("GET", "/analysis/dimensions", nil)
+
+	instance, err := NewAnalysisHandler(AnalysisHandlerConfig{
+		AuthorizedDimensions: []string{"likes", "retweets"},
+	}, &mockings.AggregateFeatureMocking{}, testRegistry, &mockings.StreamSourcesMocking{}, loggerInstance)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	instance.GetDimensions(ctx)
+
+	if writer.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, writer.Code)
+	}
+
+	dimensions := []aggregate.Dimension{}
+	if err := json.Unmarshal(writer.Body.Bytes(), &dimensions); err != nil {
+		t.Fatalf("should be able to unmarshal response body in dimensions, error %v", err)
+	}
+
+	if len(dimensions) != 2 || dimensions[0].Name != "likes" || dimensions[1].Name != "retweets" {
+		t.Fatalf("expected the authorized dimensions, got %v", dimensions)
+	}
+
+	if dimensions[1].Unit != aggregate.DefaultUnit || !slices.Equal(dimensions[1].Platforms, []string{"tweet"}) {
+		t.Errorf("expected the registry description of retweets, got %+v", dimensions[1])
+	}
+}
+
+// TestSwaggerDimensions checks that the API description lists the default dimensions of the registry.
+func TestSwaggerDimensions(t *testing.T) {
+	spec, err := os.ReadFile("../../../swagger.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	registry, err := aggregate.NewRegistry(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The dimensions depend on the configuration, the clients must list them with GET /analysis/dimensions.
+	if strings.Contains(string(spec), "enum: [likes") {
+		t.Errorf("expected the dimension parameter not to be restricted to a fixed list")
+	}
+
+	if !strings.Contains(string(spec), "/analysis/dimensions:") {
+		t.Errorf("expected the GET /analysis/dimensions route to be described")
+	}
+
+	for _, name := range registry.Names() {
+		if !strings.Contains(string(spec), "avg_"+name+":") {
+			t.Errorf("expected the avg_%s field to be described", name)
+		}
+	}
+}
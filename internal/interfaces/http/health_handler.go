@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// StreamStatus exposes the state of the upstream stream.
+type StreamStatus interface {
+	State() sse.State
+	Stats() sse.Stats
+}
+
+type HealthStatus struct {
+	StreamState sse.State `json:"stream_state"`
+	StreamStats sse.Stats `json:"stream_stats"`
+}
+
+type HealthHandler struct {
+	stream StreamStatus
+}
+
+func NewHealthHandler(stream StreamStatus) *HealthHandler {
+	return &HealthHandler{
+		stream: stream,
+	}
+}
+
+func (h *HealthHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/health", h.Get)
+}
+
+// Get reports the state of the upstream stream. The service is marked as unavailable
+// once the stream client gave up or has been closed.
+func (h *HealthHandler) Get(c *gin.Context) {
+	status := HealthStatus{
+		StreamState: h.stream.State(),
+		StreamStats: h.stream.Stats(),
+	}
+
+	if !status.StreamState.Ready() {
+		c.JSON(http.StatusServiceUnavailable, status)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
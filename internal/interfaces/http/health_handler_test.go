@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/test/mockings"
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewHealthHandler(t *testing.T) {
+	stream := &mockings.StreamStatusMocking{}
+
+	handler := NewHealthHandler(stream)
+
+	if handler.stream != stream {
+		t.Errorf("HealthHandler stream status differ from the injected one.")
+	}
+}
+
+func TestHealthHandlerRegisterRoutes(t *testing.T) {
+	router := gin.Default()
+
+	handler := NewHealthHandler(&mockings.StreamStatusMocking{})
+
+	handler.RegisterRoutes(router)
+
+	route := router.Routes()[0]
+	if route.Path != "/health" || route.Method != "GET" {
+		t.Errorf("Handler routes should be GET /health, got %s %s", route.Method, route.Path)
+	}
+}
+
+func TestHealthHandlerGet(t *testing.T) {
+	type testData struct {
+		name               string
+		state              sse.State
+		expectedStatusCode int
+	}
+
+	testCases := [...]testData{
+		{
+			name:               "Success case: connected stream",
+			state:              sse.StateConnected,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Success case: reconnecting stream",
+			state:              sse.StateReconnecting,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Fail case: stream client gave up",
+			state:              sse.StateFailed,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:               "Fail case: stream client closed",
+			state:              sse.StateClosed,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			writer := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(writer)
+			ctx.Request = httptest.NewRequest("GET", "/health", nil)
+
+			instance := NewHealthHandler(&mockings.StreamStatusMocking{StreamState: testCase.state})
+
+			instance.Get(ctx)
+
+			if writer.Code != testCase.expectedStatusCode {
+				t.Fatalf("expected status code %d, got %d", testCase.expectedStatusCode, writer.Code)
+			}
+
+			status := HealthStatus{}
+			if err := json.Unmarshal(writer.Body.Bytes(), &status); err != nil {
+				t.Fatalf("should be able to unmarshal response body in a HealthStatus, error %v", err)
+			}
+
+			if status.StreamState != testCase.state {
+				t.Errorf("expected state %s, got %s", testCase.state, status.StreamState)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
+	"github.com/gin-gonic/gin"
+)
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PostMetrics exposes the counters of the decoded posts.
+type PostMetrics interface {
+	Stats() posts.Stats
+}
+
+type MetricsHandler struct {
+	stream StreamStatus
+	posts  PostMetrics
+}
+
+func NewMetricsHandler(stream StreamStatus, posts PostMetrics) *MetricsHandler {
+	return &MetricsHandler{
+		stream: stream,
+		posts:  posts,
+	}
+}
+
+func (h *MetricsHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/metrics", h.Get)
+}
+
+// Get writes the stream and decoding counters in the Prometheus text format.
+func (h *MetricsHandler) Get(c *gin.Context) {
+	streamStats := h.stream.Stats()
+	postStats := h.posts.Stats()
+
+	ready := 0
+	if h.stream.State().Ready() {
+		ready = 1
+	}
+
+	metrics := strings.Builder{}
+	writeMetric(&metrics, "stream_ready", "gauge", "Whether the upstream stream is able to deliver events.", uint64(ready))
+	writeMetric(&metrics, "stream_reconnections_total", "counter", "Reconnection attempts to the upstream stream.", streamStats.Reconnections)
+	writeMetric(&metrics, "stream_resumes_total", "counter", "Reconnections that resumed the stream with a Last-Event-ID header.", streamStats.Resumes)
+	writeMetric(&metrics, "stream_stalls_total", "counter", "Connections aborted because no data was received before the idle timeout.", streamStats.Stalls)
+	writeMetric(&metrics, "posts_decoded_events_total", "counter", "Events decoded into posts, successfully or not.", postStats.DecodedEvents)
+	writeMetric(&metrics, "posts_decode_errors_total", "counter", "Malformed events.", postStats.DecodeErrors)
+	writeMetric(&metrics, "posts_skipped_events_total", "counter", "Well-formed events holding no single post.", postStats.SkippedEvents)
+	writeMetric(&metrics, "posts_dropped_events_total", "counter", "Events lost by the post bus because it was too slow.", postStats.DroppedEvents)
+
+	c.Data(http.StatusOK, metricsContentType, []byte(metrics.String()))
+}
+
+func writeMetric(metrics *strings.Builder, name, kind, help string, value uint64) {
+	fmt.Fprintf(metrics, "# HELP upfluence_%s %s\n# TYPE upfluence_%s %s\nupfluence_%s %d\n", name, help, name, kind, name, value)
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/test/mockings"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetricsHandlerRegisterRoutes(t *testing.T) {
+	router := gin.Default()
+
+	handler := NewMetricsHandler(&mockings.StreamStatusMocking{}, &mockings.PostMetricsMocking{})
+
+	handler.RegisterRoutes(router)
+
+	route := router.Routes()[0]
+	if route.Path != "/metrics" || route.Method != "GET" {
+		t.Errorf("Handler routes should be GET /metrics, got %s %s", route.Method, route.Path)
+	}
+}
+
+func TestMetricsHandlerGet(t *testing.T) {
+	writer := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(writer)
+	ctx.Request = httptest.NewRequest("GET", "/metrics", nil)
+
+	handler := NewMetricsHandler(&mockings.StreamStatusMocking{StreamState: sse.StateConnected}, &mockings.PostMetricsMocking{})
+
+	handler.Get(ctx)
+
+	if writer.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, writer.Code)
+	}
+
+	body := writer.Body.String()
+	for _, line := range []string{
+		"upfluence_stream_ready 1\n",
+		"upfluence_stream_reconnections_total 2\n",
+		"upfluence_posts_decoded_events_total 10\n",
+		"upfluence_posts_decode_errors_total 2\n",
+		"upfluence_posts_skipped_events_total 1\n",
+		"# TYPE upfluence_posts_decode_errors_total counter\n",
+	} {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", line, body)
+		}
+	}
+}
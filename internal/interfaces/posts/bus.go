@@ -0,0 +1,201 @@
+// Package posts decodes the stream events into posts once, and fans them out to the features.
+package posts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/broadcast"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sources"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+)
+
+var ErrBusClosed = errors.New("post bus closed")
+
+// Message is a stream event along with its decoded post.
+type Message struct {
+	// Event is the raw event, its Data holds the undecoded payload.
+	Event sse.Event
+
+	// Post is the decoded post, nil if decoding failed.
+	Post *Post
+
+	// Err is the decoding error, if any.
+	Err error
+}
+
+// Subscriber is a handle on a subscription to the bus messages.
+// It is owned by the code that created it, which must call Unsubscribe once done.
+type Subscriber = broadcast.Subscriber[Message]
+
+// Stats holds the counters of the events decoded by the bus.
+type Stats struct {
+	// DecodedEvents is the number of events decoded, successfully or not.
+	DecodedEvents uint64 `json:"decoded_events"`
+
+	// DecodeErrors is the number of malformed events.
+	DecodeErrors uint64 `json:"decode_errors"`
+
+	// SkippedEvents is the number of well-formed events holding no single post, see Skipped.
+	SkippedEvents uint64 `json:"skipped_events"`
+
+	// DroppedEvents is the number of events the bus lost because it was too slow.
+	DroppedEvents uint64 `json:"dropped_events"`
+}
+
+// Bus holds a single subscription to the source and decodes every event exactly once,
+// whatever the number of subscribers. Events are not decoded while nobody listens.
+type Bus struct {
+	source sources.Source
+	config Config
+	hub    *broadcast.Hub[Message]
+
+	// mu serializes publications and subscriptions, so that replayed events can be told apart from live ones.
+	mu sync.Mutex
+
+	// upstream is the subscription to the source, set once Run started.
+	upstream atomic.Pointer[sse.Subscriber]
+
+	decoded      atomic.Uint64
+	decodeErrors atomic.Uint64
+	skipped      atomic.Uint64
+}
+
+func NewBus(config Config, source sources.Source) *Bus {
+	return &Bus{
+		source: source,
+		config: config,
+		hub:    broadcast.NewHub[Message](),
+	}
+}
+
+// Run subscribes to the source and publishes the decoded events until ctx is done
+// or the source is closed. The bus is closed when Run returns.
+// It blocks for as long as the bus runs.
+func (b *Bus) Run(ctx context.Context) error {
+	defer b.hub.Close()
+
+	upstream, err := b.source.NewSubscriber(ctx, b.config.Upstream)
+	if err != nil {
+		return fmt.Errorf("can't subscribe to source: %w", err)
+	}
+	defer upstream.Unsubscribe()
+
+	b.upstream.Store(upstream)
+
+	for event := range upstream.Events() {
+		b.publish(event)
+	}
+
+	return nil
+}
+
+func (b *Bus) publish(event sse.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.hub.Len() == 0 {
+		return
+	}
+
+	b.hub.Publish(b.decode(event))
+}
+
+// decode returns the message of event. The events published by the source are decoded once,
+// whether they are delivered live or replayed, and however many subscribers replay them.
+func (b *Bus) decode(event sse.Event) Message {
+	return event.Memo(func(event sse.Event) any {
+		message := newMessage(event)
+
+		b.decoded.Add(1)
+		switch {
+		case message.Err == nil:
+		case Skipped(message.Err):
+			b.skipped.Add(1)
+		default:
+			b.decodeErrors.Add(1)
+		}
+
+		return message
+	}).(Message)
+}
+
+func newMessage(event sse.Event) Message {
+	post, err := decodePost(event.Data)
+	if err != nil {
+		err = fmt.Errorf("can't decode event: %w", err)
+	}
+
+	return Message{
+		Event: event,
+		Post:  post,
+		Err:   err,
+	}
+}
+
+// Subscribe creates a subscriber buffering messages as set by config.
+// The events selected by config.Replay, among the ones passing the upstream filter, are delivered first,
+// followed by the live ones.
+// sse.ErrReplayUnavailable is returned if the source replay buffer doesn't hold the requested history.
+//
+// The subscriber is removed and its channel closed when ctx is done, or when the bus is closed.
+// ErrBusClosed is returned if the bus has already been closed.
+func (b *Bus) Subscribe(ctx context.Context, config SubscriberConfig) (*Subscriber, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events, last, err := b.source.Replay(config.Replay)
+	if err != nil {
+		return nil, fmt.Errorf("can't replay events: %w", err)
+	}
+
+	// The replayed events must be the ones the bus would have delivered live.
+	events = slices.DeleteFunc(events, func(event sse.Event) bool {
+		return !b.config.Upstream.Filter.Match(event) || !config.Filter.Match(event)
+	})
+
+	backlog := make([]Message, 0, len(events))
+	for _, event := range events {
+		backlog = append(backlog, b.decode(event))
+	}
+
+	// Events recorded before the snapshot are either part of the backlog or too old, they are skipped.
+	subscriber, err := b.hub.SubscribeFrom(ctx, config.Config, func(message Message) bool {
+		return message.Event.Sequence > last && config.Filter.Match(message.Event)
+	}, backlog)
+	if errors.Is(err, broadcast.ErrHubClosed) {
+		return nil, ErrBusClosed
+	}
+
+	return subscriber, err
+}
+
+// Dropped returns the number of events the bus itself lost because it was too slow.
+// Those events are missed by every subscriber.
+func (b *Bus) Dropped() uint64 {
+	if upstream := b.upstream.Load(); upstream != nil {
+		return upstream.Dropped()
+	}
+
+	return 0
+}
+
+// Stats returns the counters of the events decoded by the bus, each one counted once whether it has been
+// delivered live or replayed.
+func (b *Bus) Stats() Stats {
+	return Stats{
+		DecodedEvents: b.decoded.Load(),
+		DecodeErrors:  b.decodeErrors.Load(),
+		SkippedEvents: b.skipped.Load(),
+		DroppedEvents: b.Dropped(),
+	}
+}
+
+// State returns the connection state of the source.
+func (b *Bus) State() sse.State {
+	return b.source.State()
+}
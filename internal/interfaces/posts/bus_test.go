@@ -0,0 +1,196 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sources"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+)
+
+var loggerInstance, _ = logs.NewLogger(logs.Config{
+	Level: "INFO",
+})
+
+// publish publishes the events to the source, with their payload as data.
+func publish(source *sources.Memory, events ...string) {
+	for _, event := range events {
+		source.Publish(sse.Event{Name: sse.DefaultEventName, Data: []byte(event)})
+	}
+}
+
+func collectMessages(sub *Subscriber) <-chan []Message {
+	result := make(chan []Message, 1)
+
+	go func() {
+		messages := []Message{}
+		for message := range sub.Events() {
+			messages = append(messages, message)
+		}
+		result <- messages
+	}()
+
+	return result
+}
+
+func TestBus(t *testing.T) {
+	source := sources.NewMemory(sse.FeedConfig{}, loggerInstance)
+	bus := NewBus(Config{}, source)
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- bus.Run(context.Background())
+	}()
+
+	all, err := bus.Subscribe(context.Background(), SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tweets, err := bus.Subscribe(context.Background(), SubscriberConfig{Filter: sse.Filter{Keys: []string{"tweet"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for bus.upstream.Load() == nil {
+		runtime.Gosched()
+	}
+
+	allMessages := collectMessages(all)
+	tweetMessages := collectMessages(tweets)
+
+	publish(source, `{"tweet":{"likes":1,"timestamp":2}}`, `invalid`, `{"pin":{"likes":3}}`)
+
+	// The events are buffered by the upstream subscription, closing the source lets the bus drain them.
+	source.Close()
+
+	if err := <-runErr; err != nil {
+		t.Fatalf("unexpected error from Run: %v", err)
+	}
+
+	messages := <-allMessages
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+
+	if messages[0].Post == nil || messages[0].Post.Platform != "tweet" || messages[0].Post.Metrics["likes"] != 1 {
+		t.Errorf("unexpected decoded post %v", messages[0].Post)
+	}
+
+	if string(messages[1].Event.Data) != "invalid" || messages[1].Err == nil || messages[1].Post != nil {
+		t.Errorf("expected a decoding error with the raw event, got %v", messages[1])
+	}
+
+	// Subscribers share the decoded post.
+	if filtered := <-tweetMessages; len(filtered) != 1 || filtered[0].Post != messages[0].Post {
+		t.Errorf("expected the filtered subscriber to receive the shared tweet, got %v", filtered)
+	}
+
+	if stats := bus.Stats(); stats.DecodedEvents != 3 || stats.DecodeErrors != 1 || stats.SkippedEvents != 0 {
+		t.Errorf("expected 3 decoded events and 1 decode error, got %+v", stats)
+	}
+
+	if _, err := bus.Subscribe(context.Background(), SubscriberConfig{}); !errors.Is(err, ErrBusClosed) {
+		t.Errorf("expected error %v, got %v", ErrBusClosed, err)
+	}
+}
+
+func TestBusRunClosedSource(t *testing.T) {
+	source := sources.NewMemory(sse.FeedConfig{}, loggerInstance)
+	source.Close()
+
+	bus := NewBus(Config{}, source)
+
+	if err := bus.Run(context.Background()); !errors.Is(err, sse.ErrClientClosed) {
+		t.Errorf("expected error %v, got %v", sse.ErrClientClosed, err)
+	}
+
+	if bus.Dropped() != 0 {
+		t.Errorf("expected no dropped event, got %d", bus.Dropped())
+	}
+
+	if bus.State() != sse.StateClosed {
+		t.Errorf("expected state %s, got %s", sse.StateClosed, bus.State())
+	}
+}
+
+func TestBusSubscribeReplay(t *testing.T) {
+	source := sources.NewMemory(sse.FeedConfig{Replay: sse.ReplayConfig{MaxEvents: 10}}, loggerInstance)
+	defer source.Close()
+
+	publish(source, `{"tweet":{"likes":1}}`, `{"pin":{"likes":2}}`, `{"tweet":{"likes":3}}`)
+
+	bus := NewBus(Config{}, source)
+
+	sub, err := bus.Subscribe(context.Background(), SubscriberConfig{
+		Filter: sse.Filter{Keys: []string{"tweet"}},
+		Replay: sse.Replay{Last: 10},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expectedLikes := range []int64{1, 3} {
+		message := <-sub.Events()
+		if message.Post == nil || message.Post.Metrics["likes"] != expectedLikes {
+			t.Errorf("expected replayed post with %d likes, got %v", expectedLikes, message.Post)
+		}
+	}
+
+	unavailable := NewBus(Config{}, sources.NewMemory(sse.FeedConfig{}, loggerInstance))
+	if _, err := unavailable.Subscribe(context.Background(), SubscriberConfig{Replay: sse.Replay{Last: 1}}); !errors.Is(err, sse.ErrReplayUnavailable) {
+		t.Errorf("expected error %v, got %v", sse.ErrReplayUnavailable, err)
+	}
+}
+
+func TestBusSubscribeReplayDecodesOnce(t *testing.T) {
+	source := sources.NewMemory(sse.FeedConfig{Replay: sse.ReplayConfig{MaxEvents: 10}}, loggerInstance)
+	defer source.Close()
+
+	publish(source, `{"tweet":{"likes":1}}`, `{"pin":{"likes":2}}`, `invalid`)
+
+	bus := NewBus(Config{}, source)
+
+	for range 2 {
+		sub, err := bus.Subscribe(context.Background(), SubscriberConfig{Replay: sse.Replay{Last: 10}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer sub.Unsubscribe()
+
+		for range 3 {
+			<-sub.Events()
+		}
+	}
+
+	if stats := bus.Stats(); stats.DecodedEvents != 3 || stats.DecodeErrors != 1 {
+		t.Errorf("expected the 3 replayed events to be decoded once, got %+v", stats)
+	}
+}
+
+func TestBusSubscribeReplayUpstreamFilter(t *testing.T) {
+	source := sources.NewMemory(sse.FeedConfig{Replay: sse.ReplayConfig{MaxEvents: 10}}, loggerInstance)
+	defer source.Close()
+
+	publish(source, `{"tweet":{"likes":1}}`, `{"pin":{"likes":2}}`)
+
+	bus := NewBus(Config{Upstream: sse.SubscriberConfig{Filter: sse.Filter{Keys: []string{"pin"}}}}, source)
+
+	sub, err := bus.Subscribe(context.Background(), SubscriberConfig{Replay: sse.Replay{Last: 10}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	message := <-sub.Events()
+	if message.Post == nil || message.Post.Platform != "pin" {
+		t.Errorf("expected the replay to skip the events filtered out upstream, got %v", message.Post)
+	}
+
+	if len(sub.Events()) != 0 {
+		t.Errorf("expected a single replayed message, got %d more", len(sub.Events()))
+	}
+}
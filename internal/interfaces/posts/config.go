@@ -0,0 +1,22 @@
+package posts
+
+import (
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/broadcast"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+)
+
+type Config struct {
+	// Upstream configures the single subscription of the bus to the source.
+	Upstream sse.SubscriberConfig `json:"upstream"`
+}
+
+// SubscriberConfig configures the buffering of a bus subscriber, and the posts it receives.
+type SubscriberConfig struct {
+	broadcast.Config
+
+	// Filter restricts the messages delivered to the subscriber, it is evaluated on the raw events.
+	Filter sse.Filter `json:"filter"`
+
+	// Replay selects the buffered events of the source delivered before the live ones.
+	Replay sse.Replay `json:"-"`
+}
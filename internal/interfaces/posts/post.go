@@ -0,0 +1,95 @@
+package posts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const timestampField = "timestamp"
+
+var (
+	ErrTooManyPosts   = errors.New("too many posts in event")
+	ErrEmptyEvent     = errors.New("empty event")
+	ErrInvalidInteger = errors.New("value is not an integer")
+)
+
+// Post is a social media post decoded from a stream event, normalized across platforms.
+type Post struct {
+	// Platform is the key wrapping the post in the event, e.g. "tweet" or "instagram_media".
+	Platform string
+
+	// Timestamp is the publication date of the post, as a unix timestamp.
+	Timestamp int64
+
+	// Metrics holds the integer fields of the post, such as likes or comments, keyed by field name.
+	// Fields absent from the event are absent from the map.
+	Metrics map[string]int64
+}
+
+// Skipped reports whether err is the decoding error of a well-formed event that holds no single post,
+// i.e. an empty or a multi-post event, as opposed to a malformed one.
+func Skipped(err error) bool {
+	return errors.Is(err, ErrTooManyPosts) || errors.Is(err, ErrEmptyEvent)
+}
+
+// decodePost decodes an event payload made of a single post keyed by its platform.
+// The timestamp must be an integer. The other integer fields are the metrics of the post,
+// non-integer fields, such as a floating-point sentiment score, are ignored.
+func decodePost(data []byte) (*Post, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, ErrEmptyEvent
+	}
+
+	rawPayload := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &rawPayload); err != nil {
+		return nil, fmt.Errorf("can't unmarshal event: %w", err)
+	}
+
+	// Event must contains only one entry
+	if len(rawPayload) > 1 {
+		return nil, ErrTooManyPosts
+	}
+
+	for platform, postPayload := range rawPayload {
+		fields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(postPayload, &fields); err != nil {
+			return nil, fmt.Errorf("can't unmarshal post payload: %w", err)
+		}
+
+		post := &Post{
+			Platform: platform,
+			Metrics:  make(map[string]int64, len(fields)),
+		}
+
+		for name, value := range fields {
+			if !isNumber(value) {
+				if name == timestampField {
+					return nil, fmt.Errorf("invalid field %s: %w", name, ErrInvalidInteger)
+				}
+
+				continue
+			}
+
+			integer, err := strconv.ParseInt(string(value), 10, 64)
+			switch {
+			case name == timestampField && err != nil:
+				return nil, fmt.Errorf("invalid field %s: %w", name, ErrInvalidInteger)
+			case name == timestampField:
+				post.Timestamp = integer
+			case err == nil:
+				post.Metrics[name] = integer
+			}
+		}
+
+		return post, nil
+	}
+
+	return nil, ErrEmptyEvent
+}
+
+func isNumber(value json.RawMessage) bool {
+	return len(value) > 0 && (value[0] == '-' || (value[0] >= '0' && value[0] <= '9'))
+}
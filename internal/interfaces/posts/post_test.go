@@ -0,0 +1,103 @@
+package posts
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestDecodePost(t *testing.T) {
+	type testData struct {
+		name           string
+		event          []byte
+		shouldFail     bool
+		skipped        bool
+		expectedResult *Post
+	}
+
+	testCases := [...]testData{
+		{
+			name:       "Success case",
+			event:      []byte(`{"yt":{"likes":2,"timestamp":1,"title":"dummy","ratio":-3}}`),
+			shouldFail: false,
+			expectedResult: &Post{
+				Platform:  "yt",
+				Timestamp: 1,
+				Metrics:   map[string]int64{"likes": 2, "ratio": -3},
+			},
+		},
+		{
+			name:       "Success case: non-integer numbers are ignored",
+			event:      []byte(`{"tweet":{"likes":3,"sentiment":0.42,"score":1e3,"huge":99999999999999999999,"timestamp":1}}`),
+			shouldFail: false,
+			expectedResult: &Post{
+				Platform:  "tweet",
+				Timestamp: 1,
+				Metrics:   map[string]int64{"likes": 3},
+			},
+		},
+		{
+			name:       "Fail case: event is not a json string",
+			event:      []byte(`invalid`),
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: empty event",
+			event:      []byte(`{}`),
+			shouldFail: true,
+			skipped:    true,
+		},
+		{
+			name:       "Fail case: blank event",
+			event:      []byte(" \n"),
+			shouldFail: true,
+			skipped:    true,
+		},
+		{
+			name:       "Fail case: multiple key",
+			event:      []byte(`{"a":{"likes":2,"timestamp":1},"b":{"likes":2,"timestamp":1}}`),
+			shouldFail: true,
+			skipped:    true,
+		},
+		{
+			name:       "Fail case: post is not an object",
+			event:      []byte(`{"yt":12}`),
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: decimal timestamp",
+			event:      []byte(`{"yt":{"likes":1,"timestamp":1.5}}`),
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: invalid timestamp",
+			event:      []byte(`{"yt":{"timestamp":"yesterday"}}`),
+			shouldFail: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			post, err := decodePost(testCase.event)
+
+			if testCase.shouldFail {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+
+				if Skipped(err) != testCase.skipped {
+					t.Errorf("expected Skipped to be %v for %v", testCase.skipped, err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if post.Platform != testCase.expectedResult.Platform ||
+					post.Timestamp != testCase.expectedResult.Timestamp ||
+					!maps.Equal(post.Metrics, testCase.expectedResult.Metrics) {
+					t.Errorf("expected %v got %v", testCase.expectedResult, post)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package sources
+
+import (
+	"context"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+)
+
+// FileConfig configures the playback of a recording, and what is kept of the played events.
+type FileConfig struct {
+	sse.PlaybackConfig
+	sse.FeedConfig
+}
+
+// File is a source playing back a recording made by the SSE client.
+type File struct {
+	*sse.Feed
+
+	config sse.PlaybackConfig
+}
+
+func NewFile(config FileConfig, log *logs.Logger) *File {
+	return &File{
+		Feed:   sse.NewFeed(config.FeedConfig, log),
+		config: config.PlaybackConfig,
+	}
+}
+
+// Listen plays the recording, from the beginning again once it ends if Loop is set.
+// Otherwise the state is set to sse.StateEnded and sse.ErrPlaybackEnded is returned.
+// If the recording can't be read, the state is set to sse.StateFailed and the error is returned.
+// It blocks for as long as the recording plays.
+func (f *File) Listen(ctx context.Context) error {
+	listenCtx, cancel := f.Bind(ctx)
+	defer cancel()
+
+	f.SetState(sse.StateConnected)
+
+	for {
+		err := sse.Play(listenCtx, f.config, f.Publish)
+		if listenCtx.Err() != nil {
+			return f.Shutdown(ctx)
+		}
+
+		if err != nil {
+			f.SetState(sse.StateFailed)
+			return err
+		}
+
+		if !f.config.Loop {
+			f.SetState(sse.StateEnded)
+			return sse.ErrPlaybackEnded
+		}
+	}
+}
+
+// Sources returns nil, the streams of the events being the ones the recording was captured from.
+func (f *File) Sources() []string {
+	return nil
+}
@@ -0,0 +1,105 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+)
+
+// createRecording records the events to a new file and returns its path.
+func createRecording(t *testing.T, events ...string) string {
+	path := filepath.Join(t.TempDir(), "stream.ndjson")
+
+	feed := sse.NewFeed(sse.FeedConfig{Record: sse.RecordConfig{Path: path}}, loggerInstance)
+	for _, data := range events {
+		feed.Publish(sse.Event{Name: sse.DefaultEventName, Data: []byte(data)})
+	}
+	feed.Close()
+
+	return path
+}
+
+func TestFileListen(t *testing.T) {
+	path := createRecording(t, "1", "2", "3")
+
+	type testData struct {
+		name           string
+		config         sse.PlaybackConfig
+		expectedError  error
+		expectedState  sse.State
+		expectedEvents int
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: recording ends",
+			config:         sse.PlaybackConfig{Path: path, Pacing: sse.PacingASAP},
+			expectedError:  sse.ErrPlaybackEnded,
+			expectedState:  sse.StateEnded,
+			expectedEvents: 3,
+		},
+		{
+			name:          "Fail case: missing recording",
+			config:        sse.PlaybackConfig{Path: path + ".missing"},
+			expectedState: sse.StateFailed,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			source := NewFile(FileConfig{
+				PlaybackConfig: testCase.config,
+				FeedConfig:     sse.FeedConfig{Replay: sse.ReplayConfig{MaxEvents: 10}},
+			}, loggerInstance)
+			defer source.Close()
+
+			err := source.Listen(context.Background())
+			if testCase.expectedError != nil && !errors.Is(err, testCase.expectedError) {
+				t.Errorf("expected error %v, got %v", testCase.expectedError, err)
+			}
+			if testCase.expectedError == nil && err == nil {
+				t.Errorf("expected an error, got nil")
+			}
+
+			if source.State() != testCase.expectedState {
+				t.Errorf("expected state %s, got %s", testCase.expectedState, source.State())
+			}
+
+			if events, _, _ := source.Replay(sse.Replay{Last: 10}); len(events) != testCase.expectedEvents {
+				t.Errorf("expected %d events, got %d", testCase.expectedEvents, len(events))
+			}
+		})
+	}
+}
+
+func TestFileListenLoop(t *testing.T) {
+	path := createRecording(t, "1", "2")
+
+	source := NewFile(FileConfig{
+		PlaybackConfig: sse.PlaybackConfig{Path: path, Pacing: sse.PacingASAP, Loop: true},
+	}, loggerInstance)
+
+	subscriber, err := source.NewSubscriber(context.Background(), sse.SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- source.Listen(context.Background())
+	}()
+
+	// The recording holds two events, a third one is only received if it was played again.
+	for range 3 {
+		<-subscriber.Events()
+	}
+
+	source.Close()
+
+	if err := <-done; !errors.Is(err, sse.ErrClientClosed) {
+		t.Errorf("expected error %v, got %v", sse.ErrClientClosed, err)
+	}
+}
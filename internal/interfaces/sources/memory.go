@@ -0,0 +1,35 @@
+package sources
+
+import (
+	"context"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+)
+
+// Memory is a source whose events are published by the caller with Publish, mostly useful in tests.
+type Memory struct {
+	*sse.Feed
+}
+
+func NewMemory(config sse.FeedConfig, log *logs.Logger) *Memory {
+	return &Memory{
+		Feed: sse.NewFeed(config, log),
+	}
+}
+
+// Listen marks the source as connected and blocks until ctx is done or the source is closed.
+func (m *Memory) Listen(ctx context.Context) error {
+	listenCtx, cancel := m.Bind(ctx)
+	defer cancel()
+
+	m.SetState(sse.StateConnected)
+	<-listenCtx.Done()
+
+	return m.Shutdown(ctx)
+}
+
+// Sources returns nil, the events being pushed with any stream name.
+func (m *Memory) Sources() []string {
+	return nil
+}
@@ -0,0 +1,44 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+)
+
+func TestMemory(t *testing.T) {
+	source := NewMemory(sse.FeedConfig{}, loggerInstance)
+
+	subscriber, err := source.NewSubscriber(context.Background(), sse.SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- source.Listen(ctx)
+	}()
+
+	source.Publish(sse.Event{Data: []byte("a")})
+
+	if event := <-subscriber.Events(); string(event.Data) != "a" {
+		t.Errorf("expected the published event, got %q", event.Data)
+	}
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, sse.ErrClientClosed) {
+		t.Errorf("expected error %v, got %v", sse.ErrClientClosed, err)
+	}
+
+	if _, ok := <-subscriber.Events(); ok {
+		t.Errorf("expected the subscriber to be closed")
+	}
+
+	if source.State() != sse.StateClosed {
+		t.Errorf("expected state %s, got %s", sse.StateClosed, source.State())
+	}
+}
@@ -0,0 +1,190 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/broadcast"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+)
+
+// mergedBufferSize is the number of events buffered between a stream and the merged feed.
+const mergedBufferSize = 1024
+
+var (
+	ErrStreamNames    = errors.New("merged streams must have distinct non-empty names")
+	ErrStreamsStopped = errors.New("every merged stream stopped")
+)
+
+// mergedStates orders the states of the streams, the merged state is the first one reached by a stream.
+var mergedStates = []sse.State{
+	sse.StateConnected,
+	sse.StateReconnecting,
+	sse.StateConnecting,
+	sse.StateStalled,
+	sse.StateFailed,
+	sse.StateEnded,
+	sse.StateClosed,
+}
+
+type stream struct {
+	name         string
+	source       Source
+	restartDelay time.Duration
+}
+
+// Merged broadcasts the events of several named streams as a single feed.
+// Each event keeps the name of its stream in its Source field.
+type Merged struct {
+	*sse.Feed
+
+	streams []stream
+	log     *logs.Logger
+}
+
+// NewMerged creates a source merging an SSE client per configuration. The replay and the recording
+// of the merged feed are set by the first configuration, the ones of the other streams are ignored.
+// ErrStreamNames is returned if the streams don't have distinct names.
+func NewMerged(configs sse.Configs, log *logs.Logger) (*Merged, error) {
+	streams := make([]stream, 0, len(configs))
+	names := map[string]bool{}
+	for _, config := range configs {
+		if config.Name == "" || names[config.Name] {
+			return nil, fmt.Errorf("%w: %q", ErrStreamNames, config.Name)
+		}
+		names[config.Name] = true
+
+		// The events are kept and recorded by the merged feed only.
+		config.FeedConfig = sse.FeedConfig{}
+
+		client, err := sse.NewSSEClient(config, log)
+		if err != nil {
+			return nil, fmt.Errorf("can't create stream %s: %w", config.Name, err)
+		}
+
+		streams = append(streams, stream{
+			name:         config.Name,
+			source:       client,
+			restartDelay: config.ReconnectionPolicy.RestartDelay(),
+		})
+	}
+
+	feedConfig := sse.FeedConfig{}
+	if len(configs) > 0 {
+		feedConfig = configs[0].FeedConfig
+	}
+
+	return newMerged(streams, feedConfig, log), nil
+}
+
+func newMerged(streams []stream, config sse.FeedConfig, log *logs.Logger) *Merged {
+	return &Merged{
+		Feed:    sse.NewFeed(config, log),
+		streams: streams,
+		log:     log,
+	}
+}
+
+// Listen runs every stream and forwards their events until ctx is done or the source is closed.
+// A stream that gives up is restarted after the restart delay of its own policy, the other streams
+// are not affected. ErrStreamsStopped is returned once every stream stopped for good.
+// It blocks for as long as a stream runs.
+func (m *Merged) Listen(ctx context.Context) error {
+	listenCtx, cancel := m.Bind(ctx)
+	defer cancel()
+
+	var forwarders, runners sync.WaitGroup
+	defer forwarders.Wait()
+
+	// Forwarders stop once listening ends, along with their subscriptions.
+	forwardCtx, stopForwarding := context.WithCancel(listenCtx)
+	defer stopForwarding()
+
+	for _, stream := range m.streams {
+		subscriber, err := stream.source.NewSubscriber(forwardCtx, sse.SubscriberConfig{
+			Config: broadcast.Config{BufferSize: mergedBufferSize, Policy: broadcast.BlockWithTimeout},
+		})
+		if err != nil {
+			return fmt.Errorf("can't subscribe to stream %s: %w", stream.name, err)
+		}
+
+		forwarders.Add(1)
+		go func() {
+			defer forwarders.Done()
+			for event := range subscriber.Events() {
+				event.Source = stream.name
+				m.Publish(event)
+			}
+		}()
+
+		runners.Add(1)
+		go func() {
+			defer runners.Done()
+			Run(listenCtx, stream.source, stream.restartDelay, m.log)
+		}()
+	}
+
+	runners.Wait()
+
+	if listenCtx.Err() != nil {
+		return m.Shutdown(ctx)
+	}
+
+	return ErrStreamsStopped
+}
+
+// State returns the most advanced state of the streams: the source is connected as long as one stream is.
+func (m *Merged) State() sse.State {
+	if state := m.Feed.State(); state == sse.StateClosed {
+		return state
+	}
+
+	states := map[sse.State]bool{}
+	for _, stream := range m.streams {
+		states[stream.source.State()] = true
+	}
+
+	for _, state := range mergedStates {
+		if states[state] {
+			return state
+		}
+	}
+
+	return sse.StateConnecting
+}
+
+// Stats returns the sum of the connection counters of the streams.
+func (m *Merged) Stats() sse.Stats {
+	stats := sse.Stats{}
+	for _, stream := range m.streams {
+		streamStats := stream.source.Stats()
+		stats.Reconnections += streamStats.Reconnections
+		stats.Resumes += streamStats.Resumes
+		stats.Stalls += streamStats.Stalls
+	}
+
+	return stats
+}
+
+// Close closes the streams and the merged feed.
+func (m *Merged) Close() {
+	m.Feed.Close()
+
+	for _, stream := range m.streams {
+		stream.source.Close()
+	}
+}
+
+// Sources returns the names of the streams, in their configuration order.
+func (m *Merged) Sources() []string {
+	names := make([]string, 0, len(m.streams))
+	for _, stream := range m.streams {
+		names = append(names, stream.name)
+	}
+
+	return names
+}
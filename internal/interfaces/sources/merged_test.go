@@ -0,0 +1,148 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/broadcast"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+)
+
+func TestMergedListen(t *testing.T) {
+	eu := NewMemory(sse.FeedConfig{}, loggerInstance)
+	us := NewMemory(sse.FeedConfig{}, loggerInstance)
+
+	merged := newMerged([]stream{{name: "eu", source: eu}, {name: "us", source: us}}, sse.FeedConfig{}, loggerInstance)
+
+	subscriber, err := merged.NewSubscriber(context.Background(), sse.SubscriberConfig{Filter: sse.Filter{Sources: []string{"us"}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- merged.Listen(ctx)
+	}()
+
+	// The streams are subscribed to before they are listened to.
+	for merged.State() != sse.StateConnected {
+		runtime.Gosched()
+	}
+
+	eu.Publish(sse.Event{Data: []byte("eu")})
+	us.Publish(sse.Event{Data: []byte("us")})
+
+	event := <-subscriber.Events()
+	if event.Source != "us" || string(event.Data) != "us" {
+		t.Errorf("expected the event of the us stream, got %v", event)
+	}
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, sse.ErrClientClosed) {
+		t.Errorf("expected error %v, got %v", sse.ErrClientClosed, err)
+	}
+
+	if eu.State() != sse.StateClosed || us.State() != sse.StateClosed {
+		t.Errorf("expected the streams to be closed, got %s and %s", eu.State(), us.State())
+	}
+}
+
+func TestMergedListenOrdering(t *testing.T) {
+	const eventsPerStream = 200
+
+	streams := []stream{}
+	for _, name := range []string{"eu", "us", "asia", "africa"} {
+		streams = append(streams, stream{name: name, source: NewMemory(sse.FeedConfig{}, loggerInstance)})
+	}
+
+	// Recording widens the gap between numbering and delivering an event.
+	config := sse.FeedConfig{Record: sse.RecordConfig{Path: filepath.Join(t.TempDir(), "merged.jsonl")}}
+	merged := newMerged(streams, config, loggerInstance)
+	defer merged.Close()
+
+	subscriber, err := merged.NewSubscriber(context.Background(), sse.SubscriberConfig{
+		Config: broadcast.Config{BufferSize: len(streams) * eventsPerStream},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	go func() {
+		_ = merged.Listen(context.Background())
+	}()
+
+	// Every stream is subscribed to before it is listened to.
+	for _, stream := range streams {
+		for stream.source.State() != sse.StateConnected {
+			runtime.Gosched()
+		}
+	}
+
+	// The forwarders of the streams publish concurrently.
+	for _, stream := range streams {
+		go func() {
+			for range eventsPerStream {
+				stream.source.(*Memory).Publish(sse.Event{Data: []byte(stream.name)})
+			}
+		}()
+	}
+
+	last := uint64(0)
+	for range len(streams) * eventsPerStream {
+		event := <-subscriber.Events()
+		if event.Sequence <= last {
+			t.Fatalf("expected a sequence greater than %d, got %d", last, event.Sequence)
+		}
+
+		last = event.Sequence
+	}
+}
+
+func TestMergedState(t *testing.T) {
+	type testData struct {
+		name          string
+		states        []sse.State
+		expectedState sse.State
+	}
+
+	testCases := [...]testData{
+		{
+			name:          "Success case: connected as long as a stream is",
+			states:        []sse.State{sse.StateFailed, sse.StateConnected},
+			expectedState: sse.StateConnected,
+		},
+		{
+			name:          "Success case: reconnecting before stalled",
+			states:        []sse.State{sse.StateStalled, sse.StateReconnecting},
+			expectedState: sse.StateReconnecting,
+		},
+		{
+			name:          "Success case: failed once every stream gave up",
+			states:        []sse.State{sse.StateFailed, sse.StateEnded},
+			expectedState: sse.StateFailed,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			streams := []stream{}
+			for _, state := range testCase.states {
+				source := NewMemory(sse.FeedConfig{}, loggerInstance)
+				source.SetState(state)
+				streams = append(streams, stream{source: source})
+			}
+
+			merged := newMerged(streams, sse.FeedConfig{}, loggerInstance)
+			defer merged.Close()
+
+			if merged.State() != testCase.expectedState {
+				t.Errorf("expected state %s, got %s", testCase.expectedState, merged.State())
+			}
+		})
+	}
+}
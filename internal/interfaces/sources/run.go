@@ -0,0 +1,38 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+)
+
+// Run listens to the source until ctx is done or the source is closed. When the source gives up,
+// it is restarted after restartDelay, or left stopped if restartDelay is zero.
+// In both cases the source state reports the failure to the health handler.
+func Run(ctx context.Context, source Source, restartDelay time.Duration, log *logs.Logger) {
+	for {
+		err := source.Listen(ctx)
+		if errors.Is(err, sse.ErrClientClosed) {
+			return
+		}
+
+		if restartDelay == 0 {
+			log.Error("Source stopped, the service is not ready anymore", logs.Field{Key: "error", Value: err.Error()})
+			return
+		}
+
+		log.Error("Source error, restarting source",
+			logs.Field{Key: "restart_delay", Value: restartDelay.String()},
+			logs.Field{Key: "error", Value: err.Error()},
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(restartDelay):
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Package sources provides the upstream event sources the posts are read from.
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+)
+
+var ErrUnknownType = errors.New("unknown source type")
+
+// Source produces the stream events and broadcasts them to its subscribers.
+// The SSE client, a recording played back, an in-memory feed and a synthetic generator are sources.
+type Source interface {
+	// Listen produces events until ctx is done or the source is closed, in which case
+	// an error wrapping sse.ErrClientClosed is returned.
+	Listen(ctx context.Context) error
+
+	// NewSubscriber subscribes to the events, see sse.Feed.NewSubscriber.
+	NewSubscriber(ctx context.Context, config sse.SubscriberConfig) (*sse.Subscriber, error)
+
+	// Replay returns the buffered events, see sse.Feed.Replay.
+	Replay(replay sse.Replay) ([]sse.Event, uint64, error)
+
+	State() sse.State
+	Stats() sse.Stats
+
+	// Sources returns the names of the streams attached to the events, see sse.Event.Source,
+	// nil if they can't be known in advance.
+	Sources() []string
+
+	// Close stops the source and closes every subscriber.
+	Close()
+}
+
+// Type selects the source of the events.
+type Type string
+
+const (
+	// TypeSSE connects to the streaming endpoints set by the sse client configuration, merged if there are several.
+	// It is the default type.
+	TypeSSE Type = "sse"
+
+	// TypeFile plays a recording back.
+	TypeFile Type = "file"
+
+	// TypeSynthetic generates random posts.
+	TypeSynthetic Type = "synthetic"
+)
+
+type Config struct {
+	// Type of the source, defaults to TypeSSE.
+	Type Type `json:"type"`
+
+	// File configures the TypeFile source.
+	File FileConfig `json:"file"`
+
+	// Synthetic configures the TypeSynthetic source.
+	Synthetic SyntheticConfig `json:"synthetic"`
+}
+
+// New creates the source selected by config. The SSE clients are configured by sseConfigs.
+func New(config Config, sseConfigs sse.Configs, log *logs.Logger) (Source, error) {
+	switch config.Type {
+	case "", TypeSSE:
+		if len(sseConfigs) > 1 {
+			return NewMerged(sseConfigs, log)
+		}
+
+		sseConfig := sse.Config{}
+		if len(sseConfigs) == 1 {
+			sseConfig = sseConfigs[0]
+		}
+
+		return sse.NewSSEClient(sseConfig, log)
+	case TypeFile:
+		return NewFile(config.File, log), nil
+	case TypeSynthetic:
+		return NewSynthetic(config.Synthetic, log), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, config.Type)
+	}
+}
+
+// RestartDelay returns the delay before restarting the source selected by config once it gave up, zero if
+// it must be left stopped. Only a single SSE stream is restarted, as set by the reconnection policy of sseConfigs:
+// merged streams are restarted by the merged source, each as set by its own policy, and the other sources end
+// for good, e.g. a recording played back without loop.
+func RestartDelay(config Config, sseConfigs sse.Configs) time.Duration {
+	if (config.Type != "" && config.Type != TypeSSE) || len(sseConfigs) != 1 {
+		return 0
+	}
+
+	return sseConfigs[0].ReconnectionPolicy.RestartDelay()
+}
@@ -0,0 +1,159 @@
+package sources
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+)
+
+var loggerInstance, _ = logs.NewLogger(logs.Config{
+	Level: "INFO",
+})
+
+func TestNew(t *testing.T) {
+	type testData struct {
+		name          string
+		config        Config
+		sseConfigs    sse.Configs
+		expectedError error
+		check         func(Source) bool
+	}
+
+	testCases := [...]testData{
+		{
+			name:   "Success case: sse by default",
+			config: Config{},
+			check: func(source Source) bool {
+				_, ok := source.(*sse.Client)
+				return ok
+			},
+		},
+		{
+			name:       "Success case: single stream",
+			sseConfigs: sse.Configs{{Name: "a"}},
+			check: func(source Source) bool {
+				_, ok := source.(*sse.Client)
+				return ok && slices.Equal(source.Sources(), []string{"a"})
+			},
+		},
+		{
+			name:       "Success case: merged streams",
+			sseConfigs: sse.Configs{{Name: "a"}, {Name: "b"}},
+			check: func(source Source) bool {
+				_, ok := source.(*Merged)
+				return ok && slices.Equal(source.Sources(), []string{"a", "b"})
+			},
+		},
+		{
+			name:          "Fail case: unnamed merged streams",
+			sseConfigs:    sse.Configs{{Name: "a"}, {}},
+			expectedError: ErrStreamNames,
+		},
+		{
+			name:          "Fail case: duplicated stream names",
+			sseConfigs:    sse.Configs{{Name: "a"}, {Name: "a"}},
+			expectedError: ErrStreamNames,
+		},
+		{
+			name:   "Success case: file",
+			config: Config{Type: TypeFile},
+			check: func(source Source) bool {
+				_, ok := source.(*File)
+				return ok && source.Sources() == nil
+			},
+		},
+		{
+			name:   "Success case: synthetic",
+			config: Config{Type: TypeSynthetic},
+			check: func(source Source) bool {
+				_, ok := source.(*Synthetic)
+				return ok && source.Sources() != nil && len(source.Sources()) == 0
+			},
+		},
+		{
+			name:          "Fail case: unknown type",
+			config:        Config{Type: "unknown"},
+			expectedError: ErrUnknownType,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			source, err := New(testCase.config, testCase.sseConfigs, loggerInstance)
+			if testCase.expectedError != nil {
+				if !errors.Is(err, testCase.expectedError) {
+					t.Errorf("expected error %v, got %v", testCase.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer source.Close()
+
+			if !testCase.check(source) {
+				t.Errorf("unexpected source type %T", source)
+			}
+		})
+	}
+}
+
+func TestRestartDelay(t *testing.T) {
+	type testData struct {
+		name           string
+		config         Config
+		sseConfigs     sse.Configs
+		expectedResult time.Duration
+	}
+
+	restarted := sse.Config{ReconnectionPolicy: sse.ReconnectionPolicy{RestartDelayMs: 60000}}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: single stream",
+			sseConfigs:     sse.Configs{restarted},
+			expectedResult: time.Minute,
+		},
+		{
+			name:           "Success case: explicit sse type",
+			config:         Config{Type: TypeSSE},
+			sseConfigs:     sse.Configs{restarted},
+			expectedResult: time.Minute,
+		},
+		{
+			name:           "Success case: single stream without restart",
+			sseConfigs:     sse.Configs{{}},
+			expectedResult: 0,
+		},
+		{
+			name:           "Success case: merged streams restart on their own",
+			sseConfigs:     sse.Configs{restarted, restarted},
+			expectedResult: 0,
+		},
+		{
+			name:           "Success case: file",
+			config:         Config{Type: TypeFile},
+			sseConfigs:     sse.Configs{restarted},
+			expectedResult: 0,
+		},
+		{
+			name:           "Success case: synthetic",
+			config:         Config{Type: TypeSynthetic},
+			sseConfigs:     sse.Configs{restarted},
+			expectedResult: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if delay := RestartDelay(testCase.config, testCase.sseConfigs); delay != testCase.expectedResult {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, delay)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package sources
+
+import (
+	"context"
+	"time"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/synthetic"
+)
+
+const defaultRatePerSecond = 10
+
+// SyntheticConfig configures the generated posts, and what is kept of them.
+type SyntheticConfig struct {
+	synthetic.Config
+	sse.FeedConfig
+
+	// RatePerSecond is the number of events generated per second, defaults to 10.
+	RatePerSecond float64 `json:"rate_per_second"`
+}
+
+// Synthetic is a source generating random posts at a steady rate.
+type Synthetic struct {
+	*sse.Feed
+
+	generator *synthetic.Generator
+	rate      float64
+}
+
+func NewSynthetic(config SyntheticConfig, log *logs.Logger) *Synthetic {
+	rate := config.RatePerSecond
+	if rate <= 0 {
+		rate = defaultRatePerSecond
+	}
+
+	return &Synthetic{
+		Feed:      sse.NewFeed(config.FeedConfig, log),
+		generator: synthetic.NewGenerator(config.Config),
+		rate:      rate,
+	}
+}
+
+// Listen generates events, blocking until ctx is done or the source is closed.
+func (s *Synthetic) Listen(ctx context.Context) error {
+	listenCtx, cancel := s.Bind(ctx)
+	defer cancel()
+
+	s.SetState(sse.StateConnected)
+
+	_ = synthetic.Emit(listenCtx, s.rate, func(now time.Time, count int) error {
+		for range count {
+			s.Publish(sse.Event{
+				Name: sse.DefaultEventName,
+				Data: s.generator.Next(now),
+			})
+		}
+
+		return nil
+	})
+
+	return s.Shutdown(ctx)
+}
+
+// Sources returns no stream, the generated events are attached to none.
+func (s *Synthetic) Sources() []string {
+	return []string{}
+}
@@ -0,0 +1,48 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/synthetic"
+)
+
+func TestSyntheticListen(t *testing.T) {
+	source := NewSynthetic(SyntheticConfig{
+		Config:        synthetic.Config{Platforms: []string{"tweet"}},
+		RatePerSecond: 1000,
+	}, loggerInstance)
+
+	subscriber, err := source.NewSubscriber(context.Background(), sse.SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- source.Listen(ctx)
+	}()
+
+	for range 20 {
+		event := <-subscriber.Events()
+
+		payload := map[string]json.RawMessage{}
+		if err := json.Unmarshal(event.Data, &payload); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, ok := payload["tweet"]; !ok || len(payload) != 1 {
+			t.Fatalf("expected a tweet, got %s", event.Data)
+		}
+	}
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, sse.ErrClientClosed) {
+		t.Errorf("expected error %v, got %v", sse.ErrClientClosed, err)
+	}
+}
@@ -0,0 +1,44 @@
+package sse
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// backoff computes jittered exponential delays between reconnection attempts.
+type backoff struct {
+	policy   ReconnectionPolicy
+	attempts int
+}
+
+func newBackoff(policy ReconnectionPolicy) *backoff {
+	return &backoff{
+		policy: policy,
+	}
+}
+
+// Next returns the delay to wait before the next attempt and counts the attempt.
+func (b *backoff) Next() time.Duration {
+	delay := float64(b.policy.initialDelay()) * math.Pow(b.policy.multiplier(), float64(b.attempts))
+	delay = min(delay, float64(b.policy.maxDelay()))
+	delay -= delay * b.policy.jitter() * rand.Float64() //nolint:gosec
+
+	b.attempts++
+
+	return time.Duration(delay)
+}
+
+// Reset restarts the backoff sequence from the initial delay.
+func (b *backoff) Reset() {
+	b.attempts = 0
+}
+
+// Exhausted reports whether maxAttempts attempts have already been made.
+func (b *backoff) Exhausted(maxAttempts int) bool {
+	if b.policy.Unlimited {
+		return false
+	}
+
+	return b.attempts > maxAttempts
+}
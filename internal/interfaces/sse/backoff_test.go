@@ -0,0 +1,124 @@
+package sse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffNext(t *testing.T) {
+	type testData struct {
+		name           string
+		policy         ReconnectionPolicy
+		expectedResult []time.Duration
+	}
+
+	testCases := [...]testData{
+		{
+			name:   "Success case: default policy",
+			policy: ReconnectionPolicy{},
+			expectedResult: []time.Duration{
+				100 * time.Millisecond,
+				200 * time.Millisecond,
+				400 * time.Millisecond,
+				800 * time.Millisecond,
+			},
+		},
+		{
+			name: "Success case: capped by max delay",
+			policy: ReconnectionPolicy{
+				InitialDelayMs: 10,
+				MaxDelayMs:     50,
+				Multiplier:     3,
+			},
+			expectedResult: []time.Duration{
+				10 * time.Millisecond,
+				30 * time.Millisecond,
+				50 * time.Millisecond,
+				50 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			backoff := newBackoff(testCase.policy)
+
+			for i, expected := range testCase.expectedResult {
+				if delay := backoff.Next(); delay != expected {
+					t.Errorf("attempt %d: expected %v, got %v", i, expected, delay)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffNextWithJitter(t *testing.T) {
+	backoff := newBackoff(ReconnectionPolicy{
+		InitialDelayMs: 100,
+		Jitter:         0.5,
+	})
+
+	for i := 0; i < 100; i++ {
+		backoff.Reset()
+
+		delay := backoff.Next()
+		if delay < 50*time.Millisecond || delay > 100*time.Millisecond {
+			t.Fatalf("expected delay between 50ms and 100ms, got %v", delay)
+		}
+	}
+}
+
+func TestBackoffReset(t *testing.T) {
+	backoff := newBackoff(ReconnectionPolicy{})
+	backoff.Next()
+	backoff.Next()
+
+	backoff.Reset()
+
+	if delay := backoff.Next(); delay != defaultInitialDelay {
+		t.Errorf("expected %v, got %v", defaultInitialDelay, delay)
+	}
+}
+
+func TestBackoffExhausted(t *testing.T) {
+	type testData struct {
+		name           string
+		policy         ReconnectionPolicy
+		attempts       int
+		maxAttempts    int
+		expectedResult bool
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: attempts left",
+			attempts:       2,
+			maxAttempts:    2,
+			expectedResult: false,
+		},
+		{
+			name:           "Success case: attempts exceeded",
+			attempts:       3,
+			maxAttempts:    2,
+			expectedResult: true,
+		},
+		{
+			name:           "Success case: unlimited attempts",
+			policy:         ReconnectionPolicy{Unlimited: true},
+			attempts:       1000,
+			maxAttempts:    2,
+			expectedResult: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			backoff := newBackoff(testCase.policy)
+			backoff.attempts = testCase.attempts
+
+			if exhausted := backoff.Exhausted(testCase.maxAttempts); exhausted != testCase.expectedResult {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, exhausted)
+			}
+		})
+	}
+}
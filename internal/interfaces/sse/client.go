@@ -1,206 +1,243 @@
 package sse
 
 import (
-	"bufio"
-	"bytes"
-	"crypto/rand"
-	"encoding/hex"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"slices"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
 )
 
+const lastEventIDHeader = "Last-Event-ID"
+
 var (
-	eventPrefix                     = []byte("data: ")
 	ErrReconnectionAttemptsExceeded = errors.New("reconnection attempts exceeded")
+	ErrStreamEnded                  = errors.New("stream ended by server")
+	ErrClientClosed                 = errors.New("sse client closed")
+	ErrStreamStalled                = errors.New("no data received from stream before idle timeout")
 )
 
 // Client represents a client for consuming Server-Sent Events (SSE) streams.
-// It manages connections to the SSE server, handles reconnections on errors, and broadcasts events to subscribers.
+// It manages connections to the SSE server, handles reconnections on errors, and broadcasts events to subscribers
+// through its feed.
 type Client struct {
-	url         string
-	subscribers []Subscriber
+	*Feed
+
+	name       string
+	url        string
+	transport  TransportConfig
+	httpClient *http.Client
 
 	maxReconnectionAttempts int
+	reconnectionPolicy      ReconnectionPolicy
+	idleTimeout             time.Duration
 
-	// Mutex to protect Subscribers
-	mu sync.Mutex
+	// Connection state, only accessed by the listening go routine.
+	lastEventID string
+	retry       time.Duration
+	connectedAt time.Time
 
-	closeChan chan struct{}
+	reconnections atomic.Uint64
+	resumes       atomic.Uint64
+	stalls        atomic.Uint64
 
 	log *logs.Logger
 }
 
-func NewSSEClient(config Config, log *logs.Logger) *Client {
+// NewSSEClient creates a client of the stream set by config. An error is returned if the transport
+// can't be set up, e.g. because a certificate or the bearer token can't be read.
+func NewSSEClient(config Config, log *logs.Logger) (*Client, error) {
+	httpClient, err := newHTTPClient(config.Transport)
+	if err != nil {
+		return nil, fmt.Errorf("can't create http client: %w", err)
+	}
+
+	if _, err := config.Transport.bearerToken(); err != nil {
+		return nil, err
+	}
+
 	return &Client{
+		Feed:                    NewFeed(config.FeedConfig, log),
+		name:                    config.Name,
 		url:                     config.ServerURL,
-		subscribers:             []Subscriber{},
+		transport:               config.Transport,
+		httpClient:              httpClient,
 		maxReconnectionAttempts: config.MaxReconnectionAttempts,
-		mu:                      sync.Mutex{},
-		closeChan:               make(chan struct{}),
+		reconnectionPolicy:      config.ReconnectionPolicy,
+		idleTimeout:             time.Duration(config.IdleTimeoutMs) * time.Millisecond,
 		log:                     log,
-	}
+	}, nil
 }
 
 // Listen establishes a connection to the SSE server and listens for events in a loop.
-// It handles reconnection logic with exponential backoff in case of errors or disconnections.
+// It handles reconnection logic with jittered exponential backoff in case of errors or disconnections,
+// as configured by the reconnection policy. Once the attempts are exhausted, the client state is set
+// to StateFailed and ErrReconnectionAttemptsExceeded is returned. Listen can then be called again.
+//
+// Cancelling ctx or calling Close aborts the request in progress, closes the client and
+// makes Listen return an error wrapping ErrClientClosed, along with the context cause if any.
 //
-// This function is blocking, it the responsibility of the caller to
+// This function is blocking, it is the responsibility of the caller to
 // launch it in a go routine.
-func (c *Client) Listen() error {
-	attempts := 0
+func (c *Client) Listen(ctx context.Context) error {
+	// A closed client stays closed, Bind would only abort the loop once the state has been overwritten.
+	if c.ctx.Err() != nil {
+		return c.Shutdown(ctx)
+	}
+
+	// Closing the client aborts the listening loop.
+	listenCtx, cancel := c.Bind(ctx)
+	defer cancel()
+
+	backoff := newBackoff(c.reconnectionPolicy)
+	c.SetState(StateConnecting)
+
 	for {
-		err := c.readStream()
-		if err != nil {
-			if attempts > c.maxReconnectionAttempts {
-				return ErrReconnectionAttemptsExceeded
-			}
+		err := c.readStream(listenCtx)
+		if listenCtx.Err() != nil {
+			return c.Shutdown(ctx)
+		}
 
-			backoff := time.Duration(attempts) * 100 * time.Millisecond
+		// A connection that stayed healthy long enough starts a new backoff sequence.
+		if !c.connectedAt.IsZero() && time.Since(c.connectedAt) >= c.reconnectionPolicy.resetAfter() {
+			backoff.Reset()
+		}
 
-			c.log.Error("SSE Client error, attempting to reconnect to stream",
-				logs.Field{Key: "backoff", Value: backoff.String()},
-				logs.Field{Key: "error", Value: err.Error()},
-			)
+		if backoff.Exhausted(c.maxReconnectionAttempts) {
+			c.SetState(StateFailed)
+			return ErrReconnectionAttemptsExceeded
+		}
 
-			time.Sleep(backoff)
-			attempts++
-			continue
+		delay := c.reconnectionDelay(backoff)
+		if errors.Is(err, ErrStreamStalled) {
+			c.SetState(StateStalled)
+		} else {
+			c.SetState(StateReconnecting)
 		}
 
-		// If connection is closed return
+		c.log.Error("SSE Client error, attempting to reconnect to stream",
+			logs.Field{Key: "stream", Value: c.name},
+			logs.Field{Key: "backoff", Value: delay.String()},
+			logs.Field{Key: "last_event_id", Value: c.lastEventID},
+			logs.Field{Key: "error", Value: err.Error()},
+		)
+
+		timer := time.NewTimer(delay)
 		select {
-		case <-c.closeChan:
-			return nil
-		default:
-			// Retry connection
+		case <-listenCtx.Done():
+			timer.Stop()
+			return c.Shutdown(ctx)
+		case <-timer.C:
 		}
-	}
-}
 
-// NewSubscriber creates and returns a new subscriber.
-// It is the caller's responsibility to call RemoveSubscriber to clean up the subscriber
-// when it is no longer needed to avoid resource leaks.
-func (c *Client) NewSubscriber() (*Subscriber, error) {
-	id, err := c.randomID()
-	if err != nil {
-		return nil, err
+		c.reconnections.Add(1)
 	}
+}
 
-	subscriber := Subscriber{
-		ID:      id,
-		Channel: make(chan []byte),
+// Stats returns the reconnection counters of the client.
+func (c *Client) Stats() Stats {
+	return Stats{
+		Reconnections: c.reconnections.Load(),
+		Resumes:       c.resumes.Load(),
+		Stalls:        c.stalls.Load(),
 	}
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.subscribers = append(c.subscribers, subscriber)
-	return &subscriber, nil
+// reconnectionDelay returns the time to wait before the next connection attempt.
+// The retry hint sent by the server is used as a lower bound.
+func (c *Client) reconnectionDelay(backoff *backoff) time.Duration {
+	return max(c.retry, backoff.Next())
 }
 
-// RemoveSubscriber removes the subscriber with the specified ID from the client's list
-// of active subscribers and closes the associated event channel.
-// This function must be called by the code that created the subscriber (e.g., after a
-// subscriber is no longer needed) to prevent resource leaks.
-func (c *Client) RemoveSubscriber(id string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.subscribers = slices.DeleteFunc(c.subscribers, func(s Subscriber) bool {
-		if s.ID == id {
-			close(s.Channel)
-			return true
-		}
+// readStream reads the stream until an error occurs. When an idle timeout is configured,
+// the connection is aborted with ErrStreamStalled if no byte is received in time.
+func (c *Client) readStream(ctx context.Context) error {
+	connCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
 
-		return false
+	watchdog := newWatchdog(c.idleTimeout, func() {
+		cancel(ErrStreamStalled)
 	})
+	defer watchdog.Stop()
+
+	err := c.consumeStream(connCtx, watchdog)
+	if errors.Is(context.Cause(connCtx), ErrStreamStalled) {
+		c.stalls.Add(1)
+		return ErrStreamStalled
+	}
+
+	return err
 }
 
-func (c *Client) readStream() error {
-	res, err := http.Get(c.url)
+func (c *Client) consumeStream(ctx context.Context, watchdog *watchdog) error {
+	c.connectedAt = time.Time{}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
 	if err != nil {
-		return fmt.Errorf("can't do request: %w", err)
+		return fmt.Errorf("can't create request: %w", err)
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return &InvalidStatusCodeError{Target: http.StatusOK, Current: res.StatusCode}
+	if err := c.transport.setHeaders(req); err != nil {
+		return fmt.Errorf("can't set request headers: %w", err)
 	}
 
-	defer res.Body.Close()
-
-	scanner := bufio.NewScanner(res.Body)
-	for {
-		select {
-		case <-c.closeChan:
-			return nil
-		default:
-			if scanner.Scan() {
-				line := scanner.Bytes()
-				if !bytes.HasPrefix(line, eventPrefix) {
-					continue
-				}
-
-				event := bytes.TrimPrefix(line, eventPrefix)
-
-				c.broadcast(event)
-				continue
-			}
-
-			if scanner.Err() != nil {
-				return scanner.Err()
-			}
-		}
+	resuming := c.lastEventID != ""
+	if resuming {
+		req.Header.Set(lastEventIDHeader, c.lastEventID)
 	}
-}
 
-func (c *Client) Close() {
-	close(c.closeChan)
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("can't do request: %w", err)
+	}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer res.Body.Close()
 
-	for _, sub := range c.subscribers {
-		select {
-		case <-sub.Channel:
-		default:
-			close(sub.Channel)
-		}
+	if res.StatusCode != http.StatusOK {
+		return &InvalidStatusCodeError{Target: http.StatusOK, Current: res.StatusCode}
 	}
-}
 
-func (c *Client) broadcast(event []byte) {
-	// Check if the client is closed
-	select {
-	case <-c.closeChan:
-		return
-	default:
+	c.connectedAt = time.Now()
+	c.SetState(StateConnected)
+
+	if resuming {
+		c.resumes.Add(1)
 	}
 
-	activeSubscribers := make([]Subscriber, 0, len(c.subscribers))
+	decoder := newDecoder(watchdog.Reader(res.Body))
+	decoder.lastEventID = c.lastEventID
+	decoder.retry = c.retry
 
-	c.mu.Lock()
-	for _, sub := range c.subscribers {
-		select {
-		case sub.Channel <- event:
-			activeSubscribers = append(activeSubscribers, sub)
-		default:
+	// Keep the resumption state for the next connection, whatever the outcome of this one.
+	defer func() {
+		c.lastEventID = decoder.lastEventID
+		c.retry = decoder.retry
+	}()
+
+	for {
+		event, err := decoder.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return ErrStreamEnded
+			}
+
+			return fmt.Errorf("can't read stream: %w", err)
 		}
+
+		event.Source = c.name
+		c.Publish(event)
 	}
-	c.subscribers = activeSubscribers
-	c.mu.Unlock()
 }
 
-func (c *Client) randomID() (string, error) {
-	bytes := make([]byte, 16)
-	if _, err := rand.Read(bytes); err != nil {
-		return "", fmt.Errorf("can't generate random id")
+// Sources returns the name of the stream, attached to its events, none if it is unnamed.
+func (c *Client) Sources() []string {
+	if c.name == "" {
+		return []string{}
 	}
 
-	return hex.EncodeToString(bytes), nil
+	return []string{c.name}
 }
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -29,96 +32,112 @@ func createSSEServerMock(interval time.Duration) *httptest.Server {
 		for i := 0; i < 5; i++ {
 			_, _ = w.Write([]byte("data: dummy event\n\n"))
 			flusher.Flush()
-			time.Sleep(interval)
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(interval):
+			}
 		}
 	}))
 }
 
+// collectEvents reads the subscriber channel until it is closed.
+func collectEvents(sub *Subscriber) <-chan []Event {
+	result := make(chan []Event, 1)
+
+	go func() {
+		receivedEvents := []Event{}
+		for event := range sub.Events() {
+			receivedEvents = append(receivedEvents, event)
+		}
+		result <- receivedEvents
+	}()
+
+	return result
+}
+
+// waitFor polls condition until it holds, failing the test if it doesn't within timeout.
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within %v", timeout)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// newTestClient creates a client, failing the test if it can't.
+func newTestClient(t *testing.T, config Config) *Client {
+	t.Helper()
+
+	client, err := NewSSEClient(config, loggerInstance)
+	if err != nil {
+		t.Fatalf("unexpected error from NewSSEClient: %v", err)
+	}
+
+	return client
+}
+
 func TestSSEClientListen(t *testing.T) {
 	server := createSSEServerMock(250 * time.Millisecond)
 	defer server.Close()
 
-	client := &Client{
-		url: server.URL,
-		subscribers: []Subscriber{
-			{
-				ID:      "id",
-				Channel: make(chan []byte),
-			},
-		},
-		closeChan:               make(chan struct{}),
-		log:                     loggerInstance,
-		maxReconnectionAttempts: 1,
+	client := newTestClient(t, Config{
+		ServerURL:               server.URL,
+		MaxReconnectionAttempts: 1,
+	})
+
+	sub, err := client.NewSubscriber(context.Background(), SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexepected error from NewSubscriber: %v", err)
 	}
 
+	events := collectEvents(sub)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	var listenError error
-	go func() {
-		listenError = client.Listen()
-	}()
-
-	defer client.Close()
-
-	var receivedEvents [][]byte
-	go func() {
-		for event := range client.subscribers[0].Channel {
-			receivedEvents = append(receivedEvents, event)
-		}
-	}()
-
-	<-ctx.Done()
-
-	if listenError != nil {
-		t.Fatalf("client.Listen returned an error but shouldn't have, error %v", listenError)
+	listenError := client.Listen(ctx)
+	if !errors.Is(listenError, ErrClientClosed) || !errors.Is(listenError, context.DeadlineExceeded) {
+		t.Fatalf("expected client.Listen to return ErrClientClosed caused by the context deadline, got %v", listenError)
 	}
 
+	receivedEvents := <-events
 	if len(receivedEvents) == 0 {
 		t.Fatal("Exepected received events but got 0")
 	}
 
 	expectedEventData := []byte("dummy event")
 	for _, event := range receivedEvents {
-		if !bytes.Equal(event, expectedEventData) {
-			t.Fatalf("Expecting event to be %q, got %q", expectedEventData, event)
+		if !bytes.Equal(event.Data, expectedEventData) {
+			t.Fatalf("Expecting event data to be %q, got %q", expectedEventData, event.Data)
 		}
 	}
 }
 
 func TestSSEClientListenReconnectionAttempsExceeded(t *testing.T) {
-	client := &Client{
-		url: "http://dummy.com/stream",
-		subscribers: []Subscriber{
-			{
-				ID:      "id",
-				Channel: make(chan []byte),
-			},
-		},
-		closeChan:               make(chan struct{}),
-		maxReconnectionAttempts: 2,
-		log:                     loggerInstance,
+	client := newTestClient(t, Config{
+		ServerURL:               "http://127.0.0.1:0/stream",
+		MaxReconnectionAttempts: 2,
+	})
+	defer client.Close()
+
+	sub, err := client.NewSubscriber(context.Background(), SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexepected error from NewSubscriber: %v", err)
 	}
 
+	events := collectEvents(sub)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var listenError error
-	go func() {
-		listenError = client.Listen()
-	}()
-
-	defer client.Close()
-
-	var receivedEvents [][]byte
-	go func() {
-		for event := range client.subscribers[0].Channel {
-			receivedEvents = append(receivedEvents, event)
-		}
-	}()
-
-	<-ctx.Done()
-
+	listenError := client.Listen(ctx)
 	if listenError == nil {
 		t.Fatalf("expected error but have nil")
 	}
@@ -127,7 +146,9 @@ func TestSSEClientListenReconnectionAttempsExceeded(t *testing.T) {
 		t.Fatalf("expected error to be ErrReconnectionAttemptsExceeded got %v", listenError)
 	}
 
-	if len(receivedEvents) != 0 {
+	client.Close()
+
+	if receivedEvents := <-events; len(receivedEvents) != 0 {
 		t.Fatal("expected 0 received events")
 	}
 }
@@ -136,26 +157,17 @@ func TestSSEClientListenWithoutSubscribers(t *testing.T) {
 	server := createSSEServerMock(250 * time.Millisecond)
 	defer server.Close()
 
-	client := &Client{
-		url:       server.URL,
-		closeChan: make(chan struct{}),
-		log:       loggerInstance,
-	}
+	client := newTestClient(t, Config{
+		ServerURL: server.URL,
+	})
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
-	defer client.Close()
-
-	var listenError error
-	go func() {
-		listenError = client.Listen()
-	}()
 
-	if listenError != nil {
+	listenError := client.Listen(ctx)
+	if !errors.Is(listenError, ErrClientClosed) {
 		t.Fatalf("can't listen to sse server: %v", listenError)
 	}
-
-	<-ctx.Done()
 }
 
 func TestSSEClientClosingClient(t *testing.T) {
@@ -163,95 +175,456 @@ func TestSSEClientClosingClient(t *testing.T) {
 	server := createSSEServerMock(2 * time.Second)
 	defer server.Close()
 
-	client := &Client{
-		url: server.URL,
-		subscribers: []Subscriber{
-			{
-				ID:      "id",
-				Channel: make(chan []byte),
-			},
-		},
+	client := newTestClient(t, Config{
+		ServerURL: server.URL,
+	})
 
-		mu:        sync.Mutex{},
-		log:       loggerInstance,
-		closeChan: make(chan struct{}),
+	sub, err := client.NewSubscriber(context.Background(), SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexepected error from NewSubscriber: %v", err)
 	}
 
-	// Context will end in 1 seconds
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-	defer cancel()
-
-	var listenError error
+	listenError := make(chan error, 1)
 	go func() {
-		listenError = client.Listen()
+		listenError <- client.Listen(context.Background())
 	}()
 
-	var receivedEvents [][]byte
-	go func() {
-		for event := range client.subscribers[0].Channel {
-			receivedEvents = append(receivedEvents, event)
-		}
-	}()
+	// The next event is sent 2 seconds after the first one.
+	select {
+	case <-sub.Events():
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a first event")
+	}
 
-	<-ctx.Done()
+	events := collectEvents(sub)
 
-	if listenError != nil {
-		client.Close()
-		t.Fatalf("unexpected error from client.Listen: %v", listenError)
+	closedAt := time.Now()
+	client.Close()
+
+	// Closing the client must abort the request in progress without waiting for the next event.
+	if err := <-listenError; !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("expected error to be ErrClientClosed, got %v", err)
+	}
+
+	if elapsed := time.Since(closedAt); elapsed > 500*time.Millisecond {
+		t.Errorf("client.Listen took %v to return after Close", elapsed)
 	}
 
+	if receivedEvents := <-events; len(receivedEvents) != 0 {
+		t.Fatalf("Expected only one received events but got %d more", len(receivedEvents))
+	}
+
+	if client.State() != StateClosed {
+		t.Errorf("expected state %s, got %s", StateClosed, client.State())
+	}
+}
+
+func TestSSEClientCloseTwice(t *testing.T) {
+	client := newTestClient(t, Config{})
+
+	if _, err := client.NewSubscriber(context.Background(), SubscriberConfig{}); err != nil {
+		t.Fatalf("unexepected error from NewSubscriber: %v", err)
+	}
+
+	client.Close()
 	client.Close()
+}
+
+func TestSSEClientListenAfterClose(t *testing.T) {
+	client := newTestClient(t, Config{
+		ServerURL: "http://127.0.0.1:0/stream",
+	})
+
+	client.Close()
+
+	if err := client.Listen(context.Background()); !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("expected error to be ErrClientClosed, got %v", err)
+	}
 
-	if len(receivedEvents) != 1 {
-		t.Fatalf("Expected only one received events but got %d", len(receivedEvents))
+	if state := client.State(); state != StateClosed {
+		t.Errorf("expected state %s, got %s", StateClosed, state)
 	}
 }
 
 func TestSSEClientNewSubscriber(t *testing.T) {
-	client := &Client{
-		subscribers: []Subscriber{},
-	}
+	client := newTestClient(t, Config{})
 
-	sub, err := client.NewSubscriber()
+	sub, err := client.NewSubscriber(context.Background(), SubscriberConfig{})
 	if err != nil {
 		t.Fatalf("unexepected error from NewSubscriber: %v", err)
 	}
 
-	if sub.ID == "" {
+	if sub.ID() == "" {
 		t.Fatal("Subscriber.ID should not be empty")
 	}
 
-	if sub.Channel == nil {
-		t.Fatal("Subscriber.Channel should not be nil")
+	if sub.Events() == nil {
+		t.Fatal("Subscriber.Events should not be nil")
 	}
 
-	if len(client.subscribers) != 1 {
-		t.Fatalf("Expected client's subscribers len to be %d, got %d", 1, len(client.subscribers))
+	if client.subscribers.Len() != 1 {
+		t.Fatalf("Expected client's subscribers len to be %d, got %d", 1, client.subscribers.Len())
 	}
 }
 
-func TestSSEClientRemoveSubscriber(t *testing.T) {
-	client := &Client{
-		subscribers: []Subscriber{},
+func TestSSEClientNewSubscriberContextDone(t *testing.T) {
+	client := newTestClient(t, Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub, err := client.NewSubscriber(ctx, SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexepected error from NewSubscriber: %v", err)
 	}
 
-	sub, err := client.NewSubscriber()
+	cancel()
+
+	select {
+	case _, ok := <-sub.Events():
+		if ok {
+			t.Fatalf("expected subscriber channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("channel not closed after context cancellation")
+	}
+
+	if client.subscribers.Len() != 0 {
+		t.Fatalf("Expected client's subscribers len to be %d, got %d", 0, client.subscribers.Len())
+	}
+}
+
+func TestSSEClientNewSubscriberClosedClient(t *testing.T) {
+	client := newTestClient(t, Config{})
+	client.Close()
+
+	if _, err := client.NewSubscriber(context.Background(), SubscriberConfig{}); !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("expected error to be ErrClientClosed, got %v", err)
+	}
+}
+
+func TestSubscriberUnsubscribe(t *testing.T) {
+	client := newTestClient(t, Config{})
+
+	sub, err := client.NewSubscriber(context.Background(), SubscriberConfig{})
 	if err != nil {
 		t.Fatalf("unexepected error from NewSubscriber: %v", err)
 	}
 
-	client.RemoveSubscriber(sub.ID)
+	sub.Unsubscribe()
 
-	if len(client.subscribers) != 0 {
-		t.Fatalf("Expected client's subscribers len to be %d, got %d", 0, len(client.subscribers))
+	if client.subscribers.Len() != 0 {
+		t.Fatalf("Expected client's subscribers len to be %d, got %d", 0, client.subscribers.Len())
 	}
 
 	select {
-	case _, ok := <-sub.Channel:
+	case _, ok := <-sub.Events():
 		if ok {
 			t.Fatalf("expected subscriber channel to be closed")
 		}
 	default:
 		t.Fatalf("channel not closed properly")
 	}
+
+	// Neither unsubscribing again nor closing the client must close the channel a second time.
+	sub.Unsubscribe()
+	client.Close()
+}
+
+func TestSSEClientListenResumesWithLastEventID(t *testing.T) {
+	var (
+		mu                 sync.Mutex
+		receivedLastIDs    []string
+		connectionsCounter int
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		receivedLastIDs = append(receivedLastIDs, r.Header.Get(lastEventIDHeader))
+		connectionsCounter++
+		id := connectionsCounter
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "id: %d\ndata: dummy event\n\n", id)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, Config{
+		ServerURL:               server.URL,
+		MaxReconnectionAttempts: 2,
+	})
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- client.Listen(context.Background())
+	}()
+
+	err := <-listenErr
+	if !errors.Is(err, ErrReconnectionAttemptsExceeded) {
+		t.Fatalf("expected error to be ErrReconnectionAttemptsExceeded got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	expectedLastIDs := []string{"", "1", "2", "3"}
+	if !slices.Equal(expectedLastIDs, receivedLastIDs) {
+		t.Fatalf("expected Last-Event-ID headers %v, got %v", expectedLastIDs, receivedLastIDs)
+	}
+
+	stats := client.Stats()
+	if stats.Reconnections != 3 {
+		t.Errorf("expected 3 reconnections, got %d", stats.Reconnections)
+	}
+
+	if stats.Resumes != 3 {
+		t.Errorf("expected 3 resumes, got %d", stats.Resumes)
+	}
+}
+
+func TestSSEClientListenNamedStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprintf(w, "data: %s\n\n", r.Header.Get("X-Region"))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, Config{
+		Name:      "eu",
+		ServerURL: server.URL,
+		Transport: TransportConfig{Headers: map[string]string{"X-Region": "europe"}},
+	})
+	defer client.Close()
+
+	sub, err := client.NewSubscriber(context.Background(), SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	go func() {
+		_ = client.Listen(context.Background())
+	}()
+
+	event := <-sub.Events()
+	if event.Source != "eu" || string(event.Data) != "europe" {
+		t.Errorf("expected an event of the eu stream sent with the configured headers, got %v", event)
+	}
+}
+
+func TestSSEClientReconnectionDelay(t *testing.T) {
+	type testData struct {
+		name           string
+		retry          time.Duration
+		attempts       int
+		expectedResult time.Duration
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: no retry hint",
+			attempts:       2,
+			expectedResult: 400 * time.Millisecond,
+		},
+		{
+			name:           "Success case: retry hint greater than backoff",
+			retry:          time.Second,
+			attempts:       2,
+			expectedResult: time.Second,
+		},
+		{
+			name:           "Success case: backoff greater than retry hint",
+			retry:          100 * time.Millisecond,
+			attempts:       2,
+			expectedResult: 400 * time.Millisecond,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client := &Client{
+				retry: testCase.retry,
+			}
+
+			backoff := newBackoff(ReconnectionPolicy{})
+			backoff.attempts = testCase.attempts
+
+			delay := client.reconnectionDelay(backoff)
+			if delay != testCase.expectedResult {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, delay)
+			}
+		})
+	}
+}
+
+func TestSSEClientListenResetsBackoffAfterHealthyConnection(t *testing.T) {
+	var connections atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connections.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, Config{
+		ServerURL:               server.URL,
+		MaxReconnectionAttempts: 1,
+		ReconnectionPolicy: ReconnectionPolicy{
+			InitialDelayMs: 1,
+			ResetAfterMs:   10,
+		},
+	})
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- client.Listen(context.Background())
+	}()
+	defer client.Close()
+
+	// Without reset, the client would give up after 2 connections.
+	waitFor(t, 5*time.Second, func() bool {
+		return connections.Load() > 4
+	})
+
+	select {
+	case err := <-listenErr:
+		t.Fatalf("client shouldn't give up, got %v", err)
+	default:
+	}
+}
+
+func TestSSEClientListenUnlimitedReconnections(t *testing.T) {
+	client := newTestClient(t, Config{
+		ServerURL:               "http://127.0.0.1:0/stream",
+		MaxReconnectionAttempts: 0,
+		ReconnectionPolicy: ReconnectionPolicy{
+			InitialDelayMs: 1,
+			MaxDelayMs:     1,
+			Unlimited:      true,
+		},
+	})
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- client.Listen(context.Background())
+	}()
+
+	waitFor(t, 5*time.Second, func() bool {
+		return client.Stats().Reconnections >= 10 && client.State() == StateReconnecting
+	})
+
+	select {
+	case err := <-listenErr:
+		t.Fatalf("client shouldn't give up, got %v", err)
+	default:
+	}
+
+	client.Close()
+}
+
+func TestSSEClientListenStateFailed(t *testing.T) {
+	client := newTestClient(t, Config{
+		ServerURL:               "http://127.0.0.1:0/stream",
+		MaxReconnectionAttempts: 1,
+		ReconnectionPolicy: ReconnectionPolicy{
+			InitialDelayMs: 1,
+		},
+	})
+
+	err := client.Listen(context.Background())
+	if !errors.Is(err, ErrReconnectionAttemptsExceeded) {
+		t.Fatalf("expected error to be ErrReconnectionAttemptsExceeded got %v", err)
+	}
+
+	if client.State() != StateFailed {
+		t.Errorf("expected state %s, got %s", StateFailed, client.State())
+	}
+
+	if client.State().Ready() {
+		t.Errorf("failed client shouldn't be ready")
+	}
+}
+
+func TestSSEClientListenIdleTimeout(t *testing.T) {
+	type testData struct {
+		name           string
+		heartbeat      time.Duration
+		expectedStalls bool
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: silent stream is stalled",
+			expectedStalls: true,
+		},
+		{
+			name:           "Success case: heartbeats keep the stream alive",
+			heartbeat:      20 * time.Millisecond,
+			expectedStalls: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write([]byte("data: dummy event\n\n"))
+				w.(http.Flusher).Flush()
+
+				if testCase.heartbeat == 0 {
+					<-r.Context().Done()
+					return
+				}
+
+				ticker := time.NewTicker(testCase.heartbeat)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-r.Context().Done():
+						return
+					case <-ticker.C:
+						_, _ = w.Write([]byte(": heartbeat\n"))
+						w.(http.Flusher).Flush()
+					}
+				}
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, Config{
+				ServerURL:     server.URL,
+				IdleTimeoutMs: 100,
+				ReconnectionPolicy: ReconnectionPolicy{
+					InitialDelayMs: 200,
+					Unlimited:      true,
+				},
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+			defer cancel()
+
+			stateDuringListen := make(chan State, 1)
+			go func() {
+				time.Sleep(150 * time.Millisecond)
+				stateDuringListen <- client.State()
+			}()
+
+			_ = client.Listen(ctx)
+
+			stalled := client.Stats().Stalls > 0
+			if stalled != testCase.expectedStalls {
+				t.Fatalf("expected stalled to be %v, got %v", testCase.expectedStalls, stalled)
+			}
+
+			expectedState := StateConnected
+			if testCase.expectedStalls {
+				expectedState = StateStalled
+			}
+
+			if state := <-stateDuringListen; state != expectedState {
+				t.Errorf("expected state %s during listening, got %s", expectedState, state)
+			}
+		})
+	}
 }
@@ -1,6 +1,118 @@
 package sse
 
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+const (
+	defaultInitialDelay = 100 * time.Millisecond
+	defaultMaxDelay     = 30 * time.Second
+	defaultMultiplier   = 2
+	defaultResetAfter   = 10 * time.Second
+)
+
 type Config struct {
-	ServerURL               string `json:"server_url"`
-	MaxReconnectionAttempts int    `json:"max_reconnection_attempts"`
+	// Name identifies the stream, it is attached to the received events. It is required when several streams are merged.
+	Name string `json:"name"`
+
+	ServerURL               string             `json:"server_url"`
+	MaxReconnectionAttempts int                `json:"max_reconnection_attempts"`
+	ReconnectionPolicy      ReconnectionPolicy `json:"reconnection_policy"`
+
+	// IdleTimeoutMs is the duration, in milliseconds, after which a connection that didn't
+	// receive any byte is considered stalled and reopened. Leave it to 0 to disable it.
+	IdleTimeoutMs int `json:"idle_timeout_ms"`
+
+	// Transport configures the HTTP connection: headers, authentication, TLS, proxy and timeouts.
+	Transport TransportConfig `json:"transport"`
+
+	// FeedConfig configures the replay and the recording of the received events.
+	FeedConfig
+}
+
+// Configs lists the streams to connect to. It is decoded from a single configuration object as well.
+type Configs []Config
+
+func (c *Configs) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		config := Config{}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return err
+		}
+
+		*c = Configs{config}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]Config)(c))
+}
+
+// ReconnectionPolicy configures the delay between two connection attempts.
+// Zero values are replaced by sensible defaults.
+type ReconnectionPolicy struct {
+	// InitialDelayMs is the delay before the first reconnection attempt, in milliseconds.
+	InitialDelayMs int `json:"initial_delay_ms"`
+
+	// MaxDelayMs caps the delay between two attempts, in milliseconds.
+	MaxDelayMs int `json:"max_delay_ms"`
+
+	// Multiplier is applied to the delay after each failed attempt.
+	Multiplier float64 `json:"multiplier"`
+
+	// Jitter is the fraction of the delay, between 0 and 1, that is randomly removed.
+	Jitter float64 `json:"jitter"`
+
+	// ResetAfterMs is the duration, in milliseconds, a connection must stay healthy
+	// before the attempts counter is reset.
+	ResetAfterMs int `json:"reset_after_ms"`
+
+	// Unlimited disables MaxReconnectionAttempts, the client never gives up.
+	Unlimited bool `json:"unlimited"`
+
+	// RestartDelayMs is the delay, in milliseconds, before the application restarts a client
+	// that gave up. Leave it to 0 to keep the client stopped.
+	RestartDelayMs int `json:"restart_delay_ms"`
+}
+
+func (p ReconnectionPolicy) initialDelay() time.Duration {
+	if p.InitialDelayMs <= 0 {
+		return defaultInitialDelay
+	}
+
+	return time.Duration(p.InitialDelayMs) * time.Millisecond
+}
+
+func (p ReconnectionPolicy) maxDelay() time.Duration {
+	if p.MaxDelayMs <= 0 {
+		return defaultMaxDelay
+	}
+
+	return time.Duration(p.MaxDelayMs) * time.Millisecond
+}
+
+func (p ReconnectionPolicy) multiplier() float64 {
+	if p.Multiplier < 1 {
+		return defaultMultiplier
+	}
+
+	return p.Multiplier
+}
+
+func (p ReconnectionPolicy) jitter() float64 {
+	return min(max(p.Jitter, 0), 1)
+}
+
+func (p ReconnectionPolicy) resetAfter() time.Duration {
+	if p.ResetAfterMs <= 0 {
+		return defaultResetAfter
+	}
+
+	return time.Duration(p.ResetAfterMs) * time.Millisecond
+}
+
+// RestartDelay returns the delay before restarting a client that gave up, zero if it must not be restarted.
+func (p ReconnectionPolicy) RestartDelay() time.Duration {
+	return time.Duration(max(p.RestartDelayMs, 0)) * time.Millisecond
 }
@@ -0,0 +1,169 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"time"
+)
+
+var byteOrderMark = []byte{0xEF, 0xBB, 0xBF}
+
+// decoder assembles events from a stream following the WHATWG EventSource format.
+// Events are dispatched on blank lines, comments are ignored and unknown fields are discarded.
+type decoder struct {
+	reader *bufio.Reader
+
+	// State shared across events, as defined by the specification.
+	lastEventID string
+	retry       time.Duration
+
+	line      []byte
+	skipLF    bool
+	firstLine bool
+}
+
+func newDecoder(r io.Reader) *decoder {
+	return &decoder{
+		reader:    bufio.NewReader(r),
+		firstLine: true,
+	}
+}
+
+// Next reads the stream until a complete event is available.
+// It returns io.EOF when the stream ends, an incomplete trailing event is discarded.
+func (d *decoder) Next() (Event, error) {
+	var (
+		name    string
+		data    []byte
+		hasData bool
+
+		// The id field only becomes the last event ID when its event is dispatched, so that
+		// an event cut by a disconnection isn't skipped when resuming.
+		id    string
+		hasID bool
+	)
+
+	for {
+		line, err := d.readLine()
+		if err != nil {
+			return Event{}, err
+		}
+
+		if len(line) == 0 {
+			if hasID {
+				d.lastEventID = id
+				hasID = false
+			}
+
+			if !hasData {
+				name = ""
+				continue
+			}
+
+			if name == "" {
+				name = DefaultEventName
+			}
+
+			return Event{
+				ID:    d.lastEventID,
+				Name:  name,
+				Data:  data[:len(data)-1],
+				Retry: d.retry,
+			}, nil
+		}
+
+		// Lines starting with a colon are comments, often used as heartbeats.
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value := splitField(line)
+		switch string(field) {
+		case "event":
+			name = string(value)
+		case "data":
+			data = append(data, value...)
+			data = append(data, '\n')
+			hasData = true
+		case "id":
+			if !bytes.ContainsRune(value, 0) {
+				id = string(value)
+				hasID = true
+			}
+		case "retry":
+			if retry, ok := parseRetry(value); ok {
+				d.retry = retry
+			}
+		}
+	}
+}
+
+// readLine returns the next line of the stream without its terminator.
+// Lines can be terminated by CRLF, LF or CR. The returned slice is only
+// valid until the next call.
+func (d *decoder) readLine() ([]byte, error) {
+	d.line = d.line[:0]
+
+	for {
+		b, err := d.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if d.skipLF {
+			d.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+
+		switch b {
+		case '\r':
+			d.skipLF = true
+			return d.trimBOM(), nil
+		case '\n':
+			return d.trimBOM(), nil
+		default:
+			d.line = append(d.line, b)
+		}
+	}
+}
+
+func (d *decoder) trimBOM() []byte {
+	if d.firstLine {
+		d.firstLine = false
+		return bytes.TrimPrefix(d.line, byteOrderMark)
+	}
+
+	return d.line
+}
+
+func splitField(line []byte) ([]byte, []byte) {
+	field, value, found := bytes.Cut(line, []byte(":"))
+	if !found {
+		return line, nil
+	}
+
+	return field, bytes.TrimPrefix(value, []byte(" "))
+}
+
+func parseRetry(value []byte) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+
+	for _, b := range value {
+		if b < '0' || b > '9' {
+			return 0, false
+		}
+	}
+
+	ms, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(ms) * time.Millisecond, true
+}
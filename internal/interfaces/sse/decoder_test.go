@@ -0,0 +1,199 @@
+package sse
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func equalEvents(a, b []Event) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].ID != b[i].ID ||
+			a[i].Name != b[i].Name ||
+			a[i].Retry != b[i].Retry ||
+			!bytes.Equal(a[i].Data, b[i].Data) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestDecoderNext(t *testing.T) {
+	type testData struct {
+		name           string
+		stream         string
+		expectedResult []Event
+	}
+
+	testCases := [...]testData{
+		{
+			name:   "Success case: single data line",
+			stream: "data: dummy event\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("dummy event")},
+			},
+		},
+		{
+			name:   "Success case: multi-line data",
+			stream: "data: first\ndata: second\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("first\nsecond")},
+			},
+		},
+		{
+			name:   "Success case: event, id and retry fields",
+			stream: "event: post\nid: 42\nretry: 1500\ndata: payload\n\n",
+			expectedResult: []Event{
+				{ID: "42", Name: "post", Data: []byte("payload"), Retry: 1500 * time.Millisecond},
+			},
+		},
+		{
+			name:   "Success case: id and retry persist across events",
+			stream: "id: 1\nretry: 10\ndata: a\n\nevent: other\ndata: b\n\n",
+			expectedResult: []Event{
+				{ID: "1", Name: DefaultEventName, Data: []byte("a"), Retry: 10 * time.Millisecond},
+				{ID: "1", Name: "other", Data: []byte("b"), Retry: 10 * time.Millisecond},
+			},
+		},
+		{
+			name:   "Success case: id of a block without data applies to the next events",
+			stream: "id: 1\n\ndata: a\n\n",
+			expectedResult: []Event{
+				{ID: "1", Name: DefaultEventName, Data: []byte("a")},
+			},
+		},
+		{
+			name:   "Success case: comments are ignored",
+			stream: ": heartbeat\n\n:another one\ndata: a\n: in the middle\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("a")},
+			},
+		},
+		{
+			name:   "Success case: value without leading space",
+			stream: "data:a\ndata:  b\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("a\n b")},
+			},
+		},
+		{
+			name:   "Success case: field without colon",
+			stream: "data\ndata\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("\n")},
+			},
+		},
+		{
+			name:   "Success case: CRLF and CR line endings",
+			stream: "data: a\r\n\r\ndata: b\r\rdata: c\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("a")},
+				{Name: DefaultEventName, Data: []byte("b")},
+				{Name: DefaultEventName, Data: []byte("c")},
+			},
+		},
+		{
+			name:   "Success case: leading byte order mark",
+			stream: "\xEF\xBB\xBFdata: a\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("a")},
+			},
+		},
+		{
+			name:   "Success case: block without data is not dispatched",
+			stream: "event: ping\n\ndata: a\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("a")},
+			},
+		},
+		{
+			name:   "Success case: invalid retry and id fields are ignored",
+			stream: "retry: 10s\nid: a\x00b\ndata: a\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("a")},
+			},
+		},
+		{
+			name:   "Success case: unknown fields are ignored",
+			stream: "foo: bar\ndata: a\n\n",
+			expectedResult: []Event{
+				{Name: DefaultEventName, Data: []byte("a")},
+			},
+		},
+		{
+			name:           "Success case: incomplete trailing event is discarded",
+			stream:         "data: a",
+			expectedResult: []Event{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			decoder := newDecoder(strings.NewReader(testCase.stream))
+
+			events := []Event{}
+			for {
+				event, err := decoder.Next()
+				if err != nil {
+					if !errors.Is(err, io.EOF) {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					break
+				}
+
+				events = append(events, event)
+			}
+
+			if !equalEvents(events, testCase.expectedResult) {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, events)
+			}
+		})
+	}
+}
+
+func TestDecoderNextTruncatedEvent(t *testing.T) {
+	decoder := newDecoder(strings.NewReader("id: 1\ndata: a\n\nid: 2\ndata: b\n"))
+
+	event, err := decoder.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if event.ID != "1" {
+		t.Errorf("expected event 1, got %q", event.ID)
+	}
+
+	if _, err := decoder.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected %v, got %v", io.EOF, err)
+	}
+
+	// The connection dropped before event 2 was complete, it must be asked for again when resuming.
+	if decoder.lastEventID != "1" {
+		t.Errorf("expected last event ID 1, got %q", decoder.lastEventID)
+	}
+}
+
+func TestDecoderNextDataIsNotShared(t *testing.T) {
+	decoder := newDecoder(strings.NewReader("data: first\n\ndata: second\n\n"))
+
+	first, err := decoder.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := decoder.Next(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(first.Data) != "first" {
+		t.Errorf("first event data was overwritten, got %q", first.Data)
+	}
+}
@@ -0,0 +1,86 @@
+package sse
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultEventName is the event type used when the server doesn't set an event field.
+const DefaultEventName = "message"
+
+// Event represents a single event dispatched by the SSE stream.
+type Event struct {
+	// ID is the last event ID set by the server when the event was dispatched.
+	ID string
+
+	// Name is the event type, DefaultEventName if the server didn't set any.
+	Name string
+
+	// Data is the event payload. Multi-line payloads are joined with a line feed.
+	Data []byte
+
+	// Retry is the reconnection time requested by the server, zero if unset.
+	Retry time.Duration
+
+	// ReceivedAt is the time the client received the event.
+	ReceivedAt time.Time
+
+	// Source is the name of the stream the event was received from, empty for an unnamed stream.
+	Source string
+
+	// Sequence numbers the events broadcast by the client, starting at 1.
+	Sequence uint64
+
+	// key memoizes the top-level JSON key of Data, so that it is computed
+	// once however many filters need it.
+	key func() string
+
+	// memo holds the value derived from the event by Memo, shared by the copies of the event.
+	memo *memo
+}
+
+type memo struct {
+	once  sync.Once
+	value any
+}
+
+// Memo returns the value computed from the event by compute. For the events published by a feed, it is
+// computed once and shared by every copy of the event, whether delivered live or replayed, so that costly
+// work such as decoding the payload isn't repeated. The event holds a single value, every caller must
+// compute the same one.
+func (e Event) Memo(compute func(Event) any) any {
+	if e.memo == nil {
+		return compute(e)
+	}
+
+	e.memo.once.Do(func() {
+		e.memo.value = compute(e)
+	})
+
+	return e.memo.value
+}
+
+// withKey returns the event with its top-level key memoized.
+func (e Event) withKey() Event {
+	data := e.Data
+	e.key = sync.OnceValue(func() string {
+		return topLevelKey(data)
+	})
+
+	return e
+}
+
+// withMemo returns the event with its top-level key memoized and ready to memoize a value, see Memo.
+func (e Event) withMemo() Event {
+	e.memo = &memo{}
+
+	return e.withKey()
+}
+
+func (e Event) topLevelKey() string {
+	if e.key == nil {
+		return topLevelKey(e.Data)
+	}
+
+	return e.key()
+}
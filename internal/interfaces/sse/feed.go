@@ -0,0 +1,184 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/broadcast"
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/logs"
+)
+
+// FeedConfig configures what a feed keeps of the events it broadcasts.
+type FeedConfig struct {
+	// Replay bounds the events kept to be replayed to new subscribers. It is disabled by default.
+	Replay ReplayConfig `json:"replay"`
+
+	// Record writes the broadcast events to rotating files. It is disabled by default.
+	Record RecordConfig `json:"record"`
+}
+
+// Feed broadcasts events to subscribers, keeps the recent ones to replay them and records them if configured.
+// It holds the state shared by every event source, the SSE client being one of them.
+type Feed struct {
+	subscribers *broadcast.Hub[Event]
+	history     *history
+	recorder    *recorder
+
+	// publishing serializes publications, so that events are recorded and delivered in sequence order.
+	publishing sync.Mutex
+
+	state atomic.Value
+
+	// ctx is cancelled when the feed is closed.
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+
+	log *logs.Logger
+}
+
+func NewFeed(config FeedConfig, log *logs.Logger) *Feed {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Feed{
+		subscribers: broadcast.NewHub[Event](),
+		history:     newHistory(config.Replay),
+		recorder:    newRecorder(config.Record),
+		ctx:         ctx,
+		cancel:      cancel,
+		log:         log,
+	}
+}
+
+// Publish numbers the event, records it and delivers it to the subscribers.
+// Events published once the feed is closed are discarded. Publish is safe for concurrent use,
+// concurrent events are delivered in the order they are numbered.
+func (f *Feed) Publish(event Event) {
+	// Check if the feed is closed
+	if f.ctx.Err() != nil {
+		return
+	}
+
+	f.publishing.Lock()
+	defer f.publishing.Unlock()
+
+	event = f.history.record(event.withMemo())
+
+	if f.recorder != nil {
+		if err := f.recorder.Write(event); err != nil {
+			f.log.Error("Feed can't record event", logs.Field{Key: "error", Value: err.Error()})
+		}
+	}
+
+	f.subscribers.Publish(event)
+}
+
+// NewSubscriber creates and returns a new subscriber buffering events as set by config.
+// The events selected by config.Replay are delivered first, followed by the live ones without gap nor duplicate.
+// ErrReplayUnavailable is returned if the replay buffer doesn't hold the requested history.
+//
+// The subscriber is removed and its channel closed when ctx is done, or when the feed is closed.
+// Subscriber.Unsubscribe can be called to release it earlier.
+// ErrClientClosed is returned if the feed has already been closed.
+func (f *Feed) NewSubscriber(ctx context.Context, config SubscriberConfig) (*Subscriber, error) {
+	// Holding the history prevents new events from being recorded until the subscriber is registered.
+	f.history.mu.Lock()
+	defer f.history.mu.Unlock()
+
+	backlog, last, err := f.history.snapshot(config.Replay, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	backlog = slices.DeleteFunc(backlog, func(event Event) bool {
+		return !config.Filter.Match(event)
+	})
+
+	// Events recorded before the snapshot may still be broadcasting, they are skipped.
+	subscriber, err := f.subscribers.SubscribeFrom(ctx, config.Config, func(event Event) bool {
+		return event.Sequence > last && config.Filter.Match(event)
+	}, backlog)
+	if errors.Is(err, broadcast.ErrHubClosed) {
+		return nil, ErrClientClosed
+	}
+
+	return subscriber, err
+}
+
+// Replay returns the buffered events selected by replay, oldest first, along with the sequence
+// of the last broadcast event: events with a greater sequence are broadcast afterwards.
+// ErrReplayUnavailable is returned if the replay buffer doesn't hold the requested history.
+func (f *Feed) Replay(replay Replay) ([]Event, uint64, error) {
+	f.history.mu.Lock()
+	defer f.history.mu.Unlock()
+
+	return f.history.snapshot(replay, time.Now())
+}
+
+// State returns the current state of the feed.
+func (f *Feed) State() State {
+	if state, ok := f.state.Load().(State); ok {
+		return state
+	}
+
+	return StateConnecting
+}
+
+// SetState sets the state of the feed, which stays StateClosed once closed.
+func (f *Feed) SetState(state State) {
+	for {
+		current := f.state.Load()
+		if current == StateClosed || f.state.CompareAndSwap(current, state) {
+			return
+		}
+	}
+}
+
+// Stats returns the connection counters, always zero for a feed that doesn't connect to anything.
+func (f *Feed) Stats() Stats {
+	return Stats{}
+}
+
+// Bind returns a context derived from ctx, also cancelled when the feed is closed.
+func (f *Feed) Bind(ctx context.Context) (context.Context, context.CancelFunc) {
+	boundCtx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(f.ctx, cancel)
+
+	return boundCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// Shutdown closes the feed once listening has been aborted by ctx or by Close, and returns
+// an error wrapping ErrClientClosed, along with the context cause if any.
+func (f *Feed) Shutdown(ctx context.Context) error {
+	f.Close()
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w: %w", ErrClientClosed, context.Cause(ctx))
+	}
+
+	return ErrClientClosed
+}
+
+// Close discards the next events and closes every subscriber channel.
+// It is safe to call Close several times.
+func (f *Feed) Close() {
+	f.closeOnce.Do(func() {
+		f.cancel()
+		f.SetState(StateClosed)
+		f.subscribers.Close()
+
+		if f.recorder != nil {
+			if err := f.recorder.Close(); err != nil {
+				f.log.Error("Feed can't close recording", logs.Field{Key: "error", Value: err.Error()})
+			}
+		}
+	})
+}
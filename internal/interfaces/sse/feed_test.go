@@ -0,0 +1,145 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/broadcast"
+)
+
+func TestFeedPublish(t *testing.T) {
+	feed := NewFeed(FeedConfig{}, loggerInstance)
+	defer feed.Close()
+
+	slow, err := feed.NewSubscriber(context.Background(), SubscriberConfig{Config: broadcast.Config{BufferSize: 1}})
+	if err != nil {
+		t.Fatalf("unexpected error from NewSubscriber: %v", err)
+	}
+
+	disconnected, err := feed.NewSubscriber(context.Background(), SubscriberConfig{Config: broadcast.Config{BufferSize: 1, Policy: Disconnect}})
+	if err != nil {
+		t.Fatalf("unexpected error from NewSubscriber: %v", err)
+	}
+
+	feed.Publish(Event{Data: []byte("1")})
+	feed.Publish(Event{Data: []byte("2")})
+
+	// A slow subscriber keeps its subscription and reports its loss.
+	if slow.Dropped() != 1 {
+		t.Errorf("expected 1 dropped event, got %d", slow.Dropped())
+	}
+
+	if feed.subscribers.Len() != 1 {
+		t.Fatalf("expected only the slow subscriber to remain")
+	}
+
+	// A disconnected subscriber receives the buffered events then its channel is closed.
+	if event := <-disconnected.Events(); string(event.Data) != "1" {
+		t.Errorf("expected event 1, got %s", event.Data)
+	}
+
+	if _, ok := <-disconnected.Events(); ok {
+		t.Errorf("expected disconnected subscriber channel to be closed")
+	}
+}
+
+func TestFeedNewSubscriberFilter(t *testing.T) {
+	feed := NewFeed(FeedConfig{}, loggerInstance)
+
+	tweets, err := feed.NewSubscriber(context.Background(), SubscriberConfig{
+		Filter: Filter{Keys: []string{"tweet"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from NewSubscriber: %v", err)
+	}
+
+	all, err := feed.NewSubscriber(context.Background(), SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error from NewSubscriber: %v", err)
+	}
+
+	feed.Publish(Event{Data: []byte(`{"tweet":{}}`)})
+	feed.Publish(Event{Data: []byte(`{"pin":{}}`)})
+	feed.Close()
+
+	if count := len(<-collectEvents(tweets)); count != 1 {
+		t.Errorf("expected 1 event for filtered subscriber, got %d", count)
+	}
+
+	if count := len(<-collectEvents(all)); count != 2 {
+		t.Errorf("expected 2 events for unfiltered subscriber, got %d", count)
+	}
+}
+
+func TestFeedNewSubscriberReplay(t *testing.T) {
+	feed := NewFeed(FeedConfig{Replay: ReplayConfig{MaxEvents: 2}}, loggerInstance)
+
+	for _, data := range []string{"1", "2", "3"} {
+		feed.Publish(Event{Data: []byte(data)})
+	}
+
+	sub, err := feed.NewSubscriber(context.Background(), SubscriberConfig{Replay: Replay{Last: 5}})
+	if err != nil {
+		t.Fatalf("unexpected error from NewSubscriber: %v", err)
+	}
+
+	feed.Publish(Event{Data: []byte("4")})
+	feed.Close()
+
+	received := []string{}
+	for _, event := range <-collectEvents(sub) {
+		received = append(received, string(event.Data))
+	}
+
+	if !slices.Equal(received, []string{"2", "3", "4"}) {
+		t.Errorf("expected replayed then live events, got %v", received)
+	}
+
+	unavailable := NewFeed(FeedConfig{}, loggerInstance)
+	defer unavailable.Close()
+
+	if _, err := unavailable.NewSubscriber(context.Background(), SubscriberConfig{Replay: Replay{Last: 1}}); !errors.Is(err, ErrReplayUnavailable) {
+		t.Errorf("expected error %v, got %v", ErrReplayUnavailable, err)
+	}
+}
+
+func TestFeedShutdown(t *testing.T) {
+	feed := NewFeed(FeedConfig{}, loggerInstance)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	boundCtx, stop := feed.Bind(ctx)
+	defer stop()
+
+	cancel()
+	<-boundCtx.Done()
+
+	if err := feed.Shutdown(ctx); !errors.Is(err, ErrClientClosed) || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error wrapping %v and %v, got %v", ErrClientClosed, context.Canceled, err)
+	}
+
+	if feed.State() != StateClosed {
+		t.Errorf("expected state %s, got %s", StateClosed, feed.State())
+	}
+
+	// Closing the feed cancels the contexts bound to it.
+	other := NewFeed(FeedConfig{}, loggerInstance)
+	otherCtx, otherStop := other.Bind(context.Background())
+	defer otherStop()
+
+	other.Close()
+	<-otherCtx.Done()
+}
+
+func TestFeedSetStateAfterClose(t *testing.T) {
+	feed := NewFeed(FeedConfig{}, loggerInstance)
+
+	feed.SetState(StateConnected)
+	feed.Close()
+	feed.SetState(StateConnecting)
+
+	if state := feed.State(); state != StateClosed {
+		t.Errorf("expected state %s, got %s", StateClosed, state)
+	}
+}
@@ -0,0 +1,65 @@
+package sse
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+)
+
+// Filter restricts the events delivered to a subscriber. Every non-empty condition must match.
+// Filters are evaluated by the broadcaster, so subscribers only receive the events they asked for.
+// The top-level key of an event is computed at most once, for all filters.
+type Filter struct {
+	// Names restricts the events to the ones with one of these event names.
+	Names []string `json:"names,omitempty"`
+
+	// Keys restricts the events to the ones whose data is a JSON object starting
+	// with one of these keys, e.g. "tweet" or "instagram_media".
+	Keys []string `json:"keys,omitempty"`
+
+	// Sources restricts the events to the ones received from one of these streams.
+	Sources []string `json:"sources,omitempty"`
+
+	// Predicate is an arbitrary condition evaluated on the event.
+	Predicate func(Event) bool `json:"-"`
+}
+
+// Match reports whether the event passes the filter.
+func (f Filter) Match(event Event) bool {
+	if len(f.Names) > 0 && !slices.Contains(f.Names, event.Name) {
+		return false
+	}
+
+	if len(f.Keys) > 0 && !slices.Contains(f.Keys, event.topLevelKey()) {
+		return false
+	}
+
+	if len(f.Sources) > 0 && !slices.Contains(f.Sources, event.Source) {
+		return false
+	}
+
+	if f.Predicate != nil && !f.Predicate(event) {
+		return false
+	}
+
+	return true
+}
+
+// topLevelKey returns the first key of a JSON object without decoding its value.
+// It returns an empty string if data isn't a JSON object.
+func topLevelKey(data []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('{') {
+		return ""
+	}
+
+	token, err = decoder.Token()
+	if err != nil {
+		return ""
+	}
+
+	key, _ := token.(string)
+	return key
+}
@@ -0,0 +1,137 @@
+package sse
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFilterMatch(t *testing.T) {
+	type testData struct {
+		name           string
+		filter         Filter
+		event          Event
+		expectedResult bool
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: empty filter matches everything",
+			filter:         Filter{},
+			event:          Event{Name: DefaultEventName, Data: []byte("not json")},
+			expectedResult: true,
+		},
+		{
+			name:           "Success case: matching event name",
+			filter:         Filter{Names: []string{"post", "comment"}},
+			event:          Event{Name: "comment"},
+			expectedResult: true,
+		},
+		{
+			name:           "Success case: event name mismatch",
+			filter:         Filter{Names: []string{"post"}},
+			event:          Event{Name: DefaultEventName},
+			expectedResult: false,
+		},
+		{
+			name:           "Success case: matching top-level key",
+			filter:         Filter{Keys: []string{"tweet", "instagram_media"}},
+			event:          Event{Data: []byte(`{"instagram_media":{"likes":10}}`)},
+			expectedResult: true,
+		},
+		{
+			name:           "Success case: top-level key mismatch",
+			filter:         Filter{Keys: []string{"tweet"}},
+			event:          Event{Data: []byte(`{"pin":{"tweet":1}}`)},
+			expectedResult: false,
+		},
+		{
+			name:           "Success case: key filter on invalid json",
+			filter:         Filter{Keys: []string{"tweet"}},
+			event:          Event{Data: []byte(`["tweet"]`)},
+			expectedResult: false,
+		},
+		{
+			name:           "Success case: matching source",
+			filter:         Filter{Sources: []string{"eu"}},
+			event:          Event{Source: "eu"},
+			expectedResult: true,
+		},
+		{
+			name:           "Success case: source mismatch",
+			filter:         Filter{Sources: []string{"eu"}},
+			event:          Event{Source: "us"},
+			expectedResult: false,
+		},
+		{
+			name: "Success case: predicate",
+			filter: Filter{Predicate: func(e Event) bool {
+				return bytes.Contains(e.Data, []byte("likes"))
+			}},
+			event:          Event{Data: []byte(`{"tweet":{"likes":1}}`)},
+			expectedResult: true,
+		},
+		{
+			name: "Success case: every condition must match",
+			filter: Filter{
+				Names:     []string{DefaultEventName},
+				Keys:      []string{"tweet"},
+				Predicate: func(Event) bool { return false },
+			},
+			event:          Event{Name: DefaultEventName, Data: []byte(`{"tweet":{}}`)},
+			expectedResult: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			result := testCase.filter.Match(testCase.event.withKey())
+			if result != testCase.expectedResult {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestTopLevelKey(t *testing.T) {
+	type testData struct {
+		name           string
+		data           string
+		expectedResult string
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: json object",
+			data:           `{"tweet":{"likes":1},"other":2}`,
+			expectedResult: "tweet",
+		},
+		{
+			name:           "Success case: leading whitespace",
+			data:           " \n{ \"pin\" : {}}",
+			expectedResult: "pin",
+		},
+		{
+			name:           "Success case: empty object",
+			data:           `{}`,
+			expectedResult: "",
+		},
+		{
+			name:           "Success case: not an object",
+			data:           `"tweet"`,
+			expectedResult: "",
+		},
+		{
+			name:           "Success case: invalid json",
+			data:           `{tweet`,
+			expectedResult: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if key := topLevelKey([]byte(testCase.data)); key != testCase.expectedResult {
+				t.Errorf("expected %q, got %q", testCase.expectedResult, key)
+			}
+		})
+	}
+}
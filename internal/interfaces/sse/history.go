@@ -0,0 +1,141 @@
+package sse
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrReplayUnavailable = errors.New("replay buffer doesn't cover the requested history")
+
+// ReplayConfig bounds the events kept in memory to be replayed to new subscribers.
+type ReplayConfig struct {
+	// MaxEvents is the number of events kept, 0 disables the replay.
+	MaxEvents int `json:"max_events"`
+
+	// MaxAgeMs is the age after which events are discarded, in milliseconds.
+	// 0 keeps them until MaxEvents is reached.
+	MaxAgeMs int `json:"max_age_ms"`
+}
+
+// Replay selects the buffered events delivered to a new subscriber before the live ones.
+// Both conditions apply when set, a zero Replay doesn't replay anything.
+type Replay struct {
+	// Since selects the events received at or after this time.
+	Since time.Time
+
+	// Last selects at most this number of the most recent events.
+	Last int
+}
+
+func (r Replay) empty() bool {
+	return r.Since.IsZero() && r.Last <= 0
+}
+
+// history is a ring buffer of the last events broadcast by the client.
+// It also numbers the events, so that subscribers can tell replayed events from live ones.
+type history struct {
+	mu sync.Mutex
+
+	events []Event
+	start  int
+	size   int
+	maxAge time.Duration
+
+	sequence uint64
+
+	// horizon is the time before which events may be missing from the buffer,
+	// either because they were evicted or because they were received before the buffer existed.
+	horizon time.Time
+}
+
+func newHistory(config ReplayConfig) *history {
+	return &history{
+		events:  make([]Event, max(config.MaxEvents, 0)),
+		maxAge:  time.Duration(config.MaxAgeMs) * time.Millisecond,
+		horizon: time.Now(),
+	}
+}
+
+// record numbers the event, stamps its reception time if unset and keeps it in the buffer.
+func (h *history) record(event Event) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sequence++
+	event.Sequence = h.sequence
+
+	if event.ReceivedAt.IsZero() {
+		event.ReceivedAt = time.Now()
+	}
+
+	if len(h.events) == 0 {
+		return event
+	}
+
+	if h.size == len(h.events) {
+		h.evict()
+	}
+
+	h.events[(h.start+h.size)%len(h.events)] = event
+	h.size++
+
+	return event
+}
+
+// snapshot returns the buffered events selected by replay, oldest first, and the sequence
+// of the last recorded event. ErrReplayUnavailable is returned if the buffer is disabled
+// or doesn't go back to replay.Since. The caller must hold mu.
+func (h *history) snapshot(replay Replay, now time.Time) ([]Event, uint64, error) {
+	if replay.empty() {
+		return nil, h.sequence, nil
+	}
+
+	if len(h.events) == 0 {
+		return nil, 0, ErrReplayUnavailable
+	}
+
+	h.prune(now)
+
+	if !replay.Since.IsZero() && !replay.Since.After(h.horizon) {
+		return nil, 0, ErrReplayUnavailable
+	}
+
+	events := make([]Event, 0, h.size)
+	for i := 0; i < h.size; i++ {
+		event := h.events[(h.start+i)%len(h.events)]
+		if event.ReceivedAt.Before(replay.Since) {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	if replay.Last > 0 && len(events) > replay.Last {
+		events = events[len(events)-replay.Last:]
+	}
+
+	return events, h.sequence, nil
+}
+
+// prune evicts the events older than the maximum age.
+func (h *history) prune(now time.Time) {
+	if h.maxAge <= 0 {
+		return
+	}
+
+	for h.size > 0 && now.Sub(h.events[h.start].ReceivedAt) > h.maxAge {
+		h.evict()
+	}
+}
+
+func (h *history) evict() {
+	evicted := h.events[h.start]
+	if evicted.ReceivedAt.After(h.horizon) {
+		h.horizon = evicted.ReceivedAt
+	}
+
+	h.events[h.start] = Event{}
+	h.start = (h.start + 1) % len(h.events)
+	h.size--
+}
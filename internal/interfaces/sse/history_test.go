@@ -0,0 +1,132 @@
+package sse
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHistorySnapshot(t *testing.T) {
+	start := time.Now()
+
+	type testData struct {
+		name             string
+		config           ReplayConfig
+		replay           Replay
+		shouldFail       bool
+		expectedSequence []uint64
+	}
+
+	testCases := [...]testData{
+		{
+			name:             "Success case: no replay",
+			config:           ReplayConfig{MaxEvents: 10},
+			replay:           Replay{},
+			expectedSequence: []uint64{},
+		},
+		{
+			name:             "Success case: last events",
+			config:           ReplayConfig{MaxEvents: 10},
+			replay:           Replay{Last: 2},
+			expectedSequence: []uint64{4, 5},
+		},
+		{
+			name:             "Success case: more events than buffered",
+			config:           ReplayConfig{MaxEvents: 3},
+			replay:           Replay{Last: 10},
+			expectedSequence: []uint64{3, 4, 5},
+		},
+		{
+			name:             "Success case: events since",
+			config:           ReplayConfig{MaxEvents: 10},
+			replay:           Replay{Since: start.Add(3 * time.Second)},
+			expectedSequence: []uint64{3, 4, 5},
+		},
+		{
+			name:             "Success case: events since and last",
+			config:           ReplayConfig{MaxEvents: 10},
+			replay:           Replay{Since: start.Add(2 * time.Second), Last: 1},
+			expectedSequence: []uint64{5},
+		},
+		{
+			name:             "Success case: maximum age",
+			config:           ReplayConfig{MaxEvents: 10, MaxAgeMs: 1500},
+			replay:           Replay{Last: 10},
+			expectedSequence: []uint64{4, 5},
+		},
+		{
+			name:       "Fail case: replay disabled",
+			config:     ReplayConfig{},
+			replay:     Replay{Last: 1},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: since before evicted events",
+			config:     ReplayConfig{MaxEvents: 3},
+			replay:     Replay{Since: start.Add(2 * time.Second)},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: since before the buffer creation",
+			config:     ReplayConfig{MaxEvents: 10},
+			replay:     Replay{Since: start.Add(-time.Second)},
+			shouldFail: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			history := newHistory(testCase.config)
+			history.horizon = start
+
+			// One event per second, the last one received 5 seconds after start.
+			for i := 1; i <= 5; i++ {
+				history.record(Event{ReceivedAt: start.Add(time.Duration(i) * time.Second)})
+			}
+
+			events, last, err := history.snapshot(testCase.replay, start.Add(5*time.Second))
+			if testCase.shouldFail {
+				if !errors.Is(err, ErrReplayUnavailable) {
+					t.Fatalf("expected error %v, got %v", ErrReplayUnavailable, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if last != 5 {
+				t.Errorf("expected last sequence 5, got %d", last)
+			}
+
+			sequences := []uint64{}
+			for _, event := range events {
+				sequences = append(sequences, event.Sequence)
+			}
+
+			if len(sequences) != len(testCase.expectedSequence) {
+				t.Fatalf("expected events %v, got %v", testCase.expectedSequence, sequences)
+			}
+
+			for i := range sequences {
+				if sequences[i] != testCase.expectedSequence[i] {
+					t.Fatalf("expected events %v, got %v", testCase.expectedSequence, sequences)
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryRecord(t *testing.T) {
+	history := newHistory(ReplayConfig{})
+
+	event := history.record(Event{})
+	if event.Sequence != 1 || event.ReceivedAt.IsZero() {
+		t.Errorf("expected event to be numbered and stamped, got %v", event)
+	}
+
+	if history.size != 0 {
+		t.Errorf("disabled history shouldn't keep events, got %d", history.size)
+	}
+}
@@ -0,0 +1,116 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const defaultPlaybackSpeed = 10
+
+var ErrPlaybackEnded = errors.New("end of recording")
+
+// Pacing defines how fast a recording is played back.
+type Pacing string
+
+const (
+	// PacingRealtime reproduces the delays between the recorded events. It is the default pacing.
+	PacingRealtime Pacing = "realtime"
+
+	// PacingAccelerated divides the recorded delays by the playback speed.
+	PacingAccelerated Pacing = "accelerated"
+
+	// PacingASAP plays the events as fast as possible.
+	PacingASAP Pacing = "asap"
+)
+
+// PlaybackConfig configures the playback of a recording made with RecordConfig.
+type PlaybackConfig struct {
+	// Path of the recording to play.
+	Path string `json:"path"`
+
+	// Pacing of the events, defaults to PacingRealtime.
+	Pacing Pacing `json:"pacing"`
+
+	// Speed is the acceleration factor of PacingAccelerated, defaults to 10.
+	Speed float64 `json:"speed"`
+
+	// Loop restarts the playback from the beginning once the recording ends.
+	Loop bool `json:"loop"`
+}
+
+// delay returns the time to wait before playing an event recorded gap after the previous one.
+func (c PlaybackConfig) delay(gap time.Duration) time.Duration {
+	switch c.Pacing {
+	case PacingASAP:
+		return 0
+	case PacingAccelerated:
+		speed := c.Speed
+		if speed <= 0 {
+			speed = defaultPlaybackSpeed
+		}
+
+		return time.Duration(float64(gap) / speed)
+	default:
+		return gap
+	}
+}
+
+// Play publishes the events of the recording set by config, paced as configured, until the recording ends.
+// The events are published without their reception time, they are stamped with their playback time.
+// Loop is left to the caller.
+func Play(ctx context.Context, config PlaybackConfig, publish func(Event)) error {
+	file, err := os.Open(config.Path)
+	if err != nil {
+		return fmt.Errorf("can't open recording: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1<<20)
+
+	var previous time.Time
+
+	for scanner.Scan() {
+		record := record{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return fmt.Errorf("can't read recording: %w", err)
+		}
+
+		if !previous.IsZero() {
+			if err := sleep(ctx, config.delay(record.ReceivedAt.Sub(previous))); err != nil {
+				return err
+			}
+		}
+		previous = record.ReceivedAt
+
+		publish(record.event())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("can't read recording: %w", err)
+	}
+
+	return nil
+}
+
+// sleep waits for d, it returns early with the ctx error if ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPlaybackConfigDelay(t *testing.T) {
+	type testData struct {
+		name           string
+		config         PlaybackConfig
+		expectedResult time.Duration
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: realtime by default",
+			config:         PlaybackConfig{},
+			expectedResult: time.Second,
+		},
+		{
+			name:           "Success case: accelerated with default speed",
+			config:         PlaybackConfig{Pacing: PacingAccelerated},
+			expectedResult: 100 * time.Millisecond,
+		},
+		{
+			name:           "Success case: accelerated",
+			config:         PlaybackConfig{Pacing: PacingAccelerated, Speed: 4},
+			expectedResult: 250 * time.Millisecond,
+		},
+		{
+			name:           "Success case: as soon as possible",
+			config:         PlaybackConfig{Pacing: PacingASAP},
+			expectedResult: 0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if delay := testCase.config.delay(time.Second); delay != testCase.expectedResult {
+				t.Errorf("expected %v, got %v", testCase.expectedResult, delay)
+			}
+		})
+	}
+}
+
+func TestPlay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stream.ndjson")
+
+	recorder := newRecorder(RecordConfig{Path: path})
+	start := time.Now()
+	for i, data := range []string{"1", "2", "3"} {
+		event := Event{Name: DefaultEventName, Data: []byte(data), ReceivedAt: start.Add(time.Duration(i) * 100 * time.Millisecond)}
+		if err := recorder.Write(event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	recorder.Close()
+
+	received := []string{}
+	err := Play(context.Background(), PlaybackConfig{Path: path, Pacing: PacingAccelerated, Speed: 2}, func(event Event) {
+		if !event.ReceivedAt.IsZero() {
+			t.Errorf("played events shouldn't keep their reception time")
+		}
+		received = append(received, string(event.Data))
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("expected the playback to be paced, took %v", elapsed)
+	}
+
+	if !slices.Equal(received, []string{"1", "2", "3"}) {
+		t.Errorf("expected the recorded events, got %v", received)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := Play(ctx, PlaybackConfig{Path: path}, func(Event) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+
+	if err := Play(context.Background(), PlaybackConfig{Path: path + ".missing"}, func(Event) {}); err == nil {
+		t.Errorf("expected error for a missing recording")
+	}
+}
+
+func TestFeedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stream.ndjson")
+
+	feed := NewFeed(FeedConfig{Record: RecordConfig{Path: path}}, loggerInstance)
+
+	feed.Publish(Event{Data: []byte("1")})
+	feed.Publish(Event{Data: []byte("2")})
+	feed.Close()
+
+	if lines := countLines(t, path); lines != 2 {
+		t.Errorf("expected 2 recorded events, got %d", lines)
+	}
+}
@@ -0,0 +1,206 @@
+package sse
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRecordMaxSizeBytes = 64 << 20
+	defaultRecordMaxFiles     = 5
+)
+
+// RecordConfig configures the recording of the received events to rotating NDJSON files.
+type RecordConfig struct {
+	// Path of the recording, the recording is disabled if empty.
+	// Rotated files are renamed <path>.1, <path>.2 and so on, the greater the older.
+	Path string `json:"path"`
+
+	// MaxSizeBytes is the size after which the file is rotated, defaults to 64MiB.
+	MaxSizeBytes int64 `json:"max_size_bytes"`
+
+	// MaxFiles is the number of rotated files kept, defaults to 5.
+	MaxFiles int `json:"max_files"`
+}
+
+func (c RecordConfig) maxSizeBytes() int64 {
+	if c.MaxSizeBytes <= 0 {
+		return defaultRecordMaxSizeBytes
+	}
+
+	return c.MaxSizeBytes
+}
+
+func (c RecordConfig) maxFiles() int {
+	if c.MaxFiles <= 0 {
+		return defaultRecordMaxFiles
+	}
+
+	return c.MaxFiles
+}
+
+// record is a recorded event, written as one JSON object per line.
+type record struct {
+	ReceivedAt time.Time `json:"received_at"`
+	Source     string    `json:"source,omitempty"`
+	ID         string    `json:"id,omitempty"`
+	Name       string    `json:"event,omitempty"`
+	Data       string    `json:"data"`
+	RetryMs    int64     `json:"retry_ms,omitempty"`
+}
+
+func newRecord(event Event) record {
+	return record{
+		ReceivedAt: event.ReceivedAt,
+		Source:     event.Source,
+		ID:         event.ID,
+		Name:       event.Name,
+		Data:       string(event.Data),
+		RetryMs:    event.Retry.Milliseconds(),
+	}
+}
+
+func (r record) event() Event {
+	return Event{
+		ID:     r.ID,
+		Name:   r.Name,
+		Data:   []byte(r.Data),
+		Retry:  time.Duration(r.RetryMs) * time.Millisecond,
+		Source: r.Source,
+	}
+}
+
+// recorder appends events to a file, rotating it once it exceeds the maximum size.
+// The file is opened on the first write.
+type recorder struct {
+	config RecordConfig
+
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	size   int64
+	closed bool
+}
+
+func newRecorder(config RecordConfig) *recorder {
+	if config.Path == "" {
+		return nil
+	}
+
+	return &recorder{
+		config: config,
+	}
+}
+
+// Write records the event. Every event is flushed, so that the recording is usable while being written.
+// Events written once the recorder is closed are discarded.
+func (r *recorder) Write(event Event) error {
+	line, err := json.Marshal(newRecord(event))
+	if err != nil {
+		return fmt.Errorf("can't marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	if r.file != nil && r.size+int64(len(line)) > r.config.maxSizeBytes() {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.writer.Write(line); err != nil {
+		return fmt.Errorf("can't write recording: %w", err)
+	}
+
+	r.size += int64(len(line))
+
+	if err := r.writer.Flush(); err != nil {
+		return fmt.Errorf("can't write recording: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the current file and stops the recording.
+func (r *recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+
+	return r.close()
+}
+
+func (r *recorder) open() error {
+	file, err := os.OpenFile(r.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("can't open recording: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("can't open recording: %w", err)
+	}
+
+	r.file = file
+	r.writer = bufio.NewWriter(file)
+	r.size = info.Size()
+
+	return nil
+}
+
+func (r *recorder) close() error {
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.file.Close()
+	r.file = nil
+	r.writer = nil
+
+	return err
+}
+
+// rotate shifts the rotated files, dropping the oldest one, and renames the current file.
+func (r *recorder) rotate() error {
+	if err := r.close(); err != nil {
+		return fmt.Errorf("can't close recording: %w", err)
+	}
+
+	maxFiles := r.config.maxFiles()
+	_ = os.Remove(r.rotatedPath(maxFiles))
+
+	for i := maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(r.rotatedPath(i), r.rotatedPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("can't rotate recording: %w", err)
+		}
+	}
+
+	if err := os.Rename(r.config.Path, r.rotatedPath(1)); err != nil {
+		return fmt.Errorf("can't rotate recording: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recorder) rotatedPath(index int) string {
+	return r.config.Path + "." + strconv.Itoa(index)
+}
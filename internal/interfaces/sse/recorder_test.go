@@ -0,0 +1,73 @@
+package sse
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("can't open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines++
+	}
+
+	return lines
+}
+
+func TestRecorderWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stream.ndjson")
+
+	recorder := newRecorder(RecordConfig{
+		Path:         path,
+		MaxSizeBytes: 150,
+		MaxFiles:     2,
+	})
+
+	// Each line is about 90 bytes long, so that every file holds a single event.
+	for i := 0; i < 5; i++ {
+		event := Event{ID: "1", Name: "post", Data: []byte(`{"tweet":{}}`), ReceivedAt: time.Unix(0, 0)}
+		if err := recorder.Write(event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		if lines := countLines(t, name); lines != 1 {
+			t.Errorf("expected 1 event in %s, got %d", name, lines)
+		}
+	}
+
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 rotated files to be kept")
+	}
+
+	if err := recorder.Write(Event{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if lines := countLines(t, path); lines != 1 {
+		t.Errorf("expected closed recorder to discard events, got %d events", lines)
+	}
+}
+
+func TestNewRecorderDisabled(t *testing.T) {
+	if recorder := newRecorder(RecordConfig{}); recorder != nil {
+		t.Errorf("expected recorder to be disabled without path")
+	}
+}
@@ -0,0 +1,20 @@
+package sse
+
+// State describes the connection state of a Client.
+type State string
+
+const (
+	StateConnecting   State = "connecting"
+	StateConnected    State = "connected"
+	StateReconnecting State = "reconnecting"
+	StateStalled      State = "stalled"
+	StateFailed       State = "failed"
+	StateEnded        State = "ended"
+	StateClosed       State = "closed"
+)
+
+// Ready reports whether the client is able to deliver events, or is about to.
+// A client whose stream stalled, that gave up, finished its playback or has been closed is not ready.
+func (s State) Ready() bool {
+	return s != StateStalled && s != StateFailed && s != StateEnded && s != StateClosed
+}
@@ -0,0 +1,13 @@
+package sse
+
+// Stats holds the counters describing the connection history of a Client.
+type Stats struct {
+	// Reconnections is the number of reconnection attempts made after an error or a disconnection.
+	Reconnections uint64 `json:"reconnections"`
+
+	// Resumes is the number of successful reconnections that resumed the stream with a Last-Event-ID header.
+	Resumes uint64 `json:"resumes"`
+
+	// Stalls is the number of connections aborted because no data was received before the idle timeout.
+	Stalls uint64 `json:"stalls"`
+}
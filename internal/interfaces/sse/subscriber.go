@@ -1,6 +1,28 @@
 package sse
 
-type Subscriber struct {
-	ID      string
-	Channel chan []byte
+import "github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/broadcast"
+
+// SlowConsumerPolicy defines what happens to an event when a subscriber buffer is full.
+type SlowConsumerPolicy = broadcast.SlowConsumerPolicy
+
+const (
+	DropNewest       = broadcast.DropNewest
+	DropOldest       = broadcast.DropOldest
+	BlockWithTimeout = broadcast.BlockWithTimeout
+	Disconnect       = broadcast.Disconnect
+)
+
+// Subscriber is a handle on a subscription to the client events.
+// It is owned by the code that created it, which must call Unsubscribe once done.
+type Subscriber = broadcast.Subscriber[Event]
+
+// SubscriberConfig configures the buffering of a subscriber, and the events it receives.
+type SubscriberConfig struct {
+	broadcast.Config
+
+	// Filter restricts the events delivered to the subscriber. All events are delivered by default.
+	Filter Filter `json:"filter"`
+
+	// Replay selects the buffered events delivered before the live ones.
+	Replay Replay `json:"-"`
 }
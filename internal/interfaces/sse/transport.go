@@ -0,0 +1,188 @@
+package sse
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDialTimeout         = 10 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+var (
+	ErrMissingBearerToken = errors.New("bearer token is empty")
+	ErrInvalidCABundle    = errors.New("no certificate found in CA bundle")
+)
+
+// TransportConfig configures the HTTP connection to the stream.
+// The stream requests always accept text/event-stream and ask not to be cached.
+type TransportConfig struct {
+	// Headers are added to the stream requests.
+	Headers map[string]string `json:"headers"`
+
+	// BearerTokenFile is the path of a file holding the token sent in the Authorization header.
+	// The file is read again on every connection, so that a rotated token is picked up.
+	BearerTokenFile string `json:"bearer_token_file"`
+
+	// BearerTokenEnv is the environment variable holding the token, used if BearerTokenFile is empty.
+	BearerTokenEnv string `json:"bearer_token_env"`
+
+	// CAFile is the path of a PEM bundle of the authorities trusted in addition to the system ones.
+	CAFile string `json:"ca_file"`
+
+	// CertFile and KeyFile are the paths of the PEM client certificate and key presented to the server.
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+
+	// ProxyURL is the proxy the requests go through. The proxy environment variables are used if empty.
+	ProxyURL string `json:"proxy_url"`
+
+	// DialTimeoutMs bounds the TCP connection establishment, in milliseconds. Defaults to 10s.
+	DialTimeoutMs int `json:"dial_timeout_ms"`
+
+	// TLSHandshakeTimeoutMs bounds the TLS handshake, in milliseconds. Defaults to 10s.
+	TLSHandshakeTimeoutMs int `json:"tls_handshake_timeout_ms"`
+
+	// ResponseHeaderTimeoutMs bounds the wait for the response headers, in milliseconds. Leave it to 0 to disable it.
+	ResponseHeaderTimeoutMs int `json:"response_header_timeout_ms"`
+}
+
+func (c TransportConfig) dialTimeout() time.Duration {
+	if c.DialTimeoutMs <= 0 {
+		return defaultDialTimeout
+	}
+
+	return time.Duration(c.DialTimeoutMs) * time.Millisecond
+}
+
+func (c TransportConfig) tlsHandshakeTimeout() time.Duration {
+	if c.TLSHandshakeTimeoutMs <= 0 {
+		return defaultTLSHandshakeTimeout
+	}
+
+	return time.Duration(c.TLSHandshakeTimeoutMs) * time.Millisecond
+}
+
+// newHTTPClient returns the client used to open the stream. It has no overall timeout, the stream being endless.
+func newHTTPClient(config TransportConfig) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse proxy url: %w", err)
+		}
+
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   config.dialTimeout(),
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 proxy,
+			DialContext:           dialer.DialContext,
+			TLSClientConfig:       tlsConfig,
+			TLSHandshakeTimeout:   config.tlsHandshakeTimeout(),
+			ResponseHeaderTimeout: time.Duration(config.ResponseHeaderTimeoutMs) * time.Millisecond,
+			ForceAttemptHTTP2:     true,
+		},
+	}, nil
+}
+
+func newTLSConfig(config TransportConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if config.CAFile != "" {
+		bundle, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCABundle, config.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't load client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
+
+// bearerToken returns the token to authenticate with, empty if none is configured.
+func (c TransportConfig) bearerToken() (string, error) {
+	var token string
+
+	switch {
+	case c.BearerTokenFile != "":
+		content, err := os.ReadFile(c.BearerTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("can't read bearer token: %w", err)
+		}
+
+		token = strings.TrimSpace(string(content))
+	case c.BearerTokenEnv != "":
+		token = os.Getenv(c.BearerTokenEnv)
+	default:
+		return "", nil
+	}
+
+	if token == "" {
+		return "", ErrMissingBearerToken
+	}
+
+	return token, nil
+}
+
+// setHeaders sets the headers of a stream request.
+func (c TransportConfig) setHeaders(req *http.Request) error {
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	for key, value := range c.Headers {
+		req.Header.Set(key, value)
+	}
+
+	token, err := c.bearerToken()
+	if err != nil {
+		return err
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return nil
+}
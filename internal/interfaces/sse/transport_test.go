@@ -0,0 +1,194 @@
+package sse
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransportConfigBearerToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenPath := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenPath, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("SSE_TEST_TOKEN", "from-env")
+	t.Setenv("SSE_TEST_EMPTY_TOKEN", "")
+
+	type testData struct {
+		name           string
+		config         TransportConfig
+		shouldFail     bool
+		expectedResult string
+	}
+
+	testCases := [...]testData{
+		{
+			name:           "Success case: no token",
+			config:         TransportConfig{},
+			expectedResult: "",
+		},
+		{
+			name:           "Success case: token file is trimmed",
+			config:         TransportConfig{BearerTokenFile: tokenPath, BearerTokenEnv: "SSE_TEST_TOKEN"},
+			expectedResult: "from-file",
+		},
+		{
+			name:           "Success case: token from environment",
+			config:         TransportConfig{BearerTokenEnv: "SSE_TEST_TOKEN"},
+			expectedResult: "from-env",
+		},
+		{
+			name:       "Fail case: missing token file",
+			config:     TransportConfig{BearerTokenFile: tokenPath + ".missing"},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: empty environment variable",
+			config:     TransportConfig{BearerTokenEnv: "SSE_TEST_EMPTY_TOKEN"},
+			shouldFail: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			token, err := testCase.config.bearerToken()
+			if testCase.shouldFail {
+				if err == nil {
+					t.Errorf("expected an error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if token != testCase.expectedResult {
+				t.Errorf("expected %q, got %q", testCase.expectedResult, token)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	dir := t.TempDir()
+	invalidBundle := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalidBundle, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type testData struct {
+		name          string
+		config        TransportConfig
+		shouldFail    bool
+		expectedError error
+	}
+
+	testCases := [...]testData{
+		{
+			name:   "Success case: default transport",
+			config: TransportConfig{ProxyURL: "http://proxy.internal:3128"},
+		},
+		{
+			name:       "Fail case: missing CA bundle",
+			config:     TransportConfig{CAFile: filepath.Join(dir, "missing.pem")},
+			shouldFail: true,
+		},
+		{
+			name:          "Fail case: CA bundle without certificate",
+			config:        TransportConfig{CAFile: invalidBundle},
+			shouldFail:    true,
+			expectedError: ErrInvalidCABundle,
+		},
+		{
+			name:       "Fail case: client certificate without key",
+			config:     TransportConfig{CertFile: invalidBundle},
+			shouldFail: true,
+		},
+		{
+			name:       "Fail case: invalid proxy url",
+			config:     TransportConfig{ProxyURL: "://proxy"},
+			shouldFail: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := newHTTPClient(testCase.config)
+			if !testCase.shouldFail {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected an error, got nil")
+			}
+
+			if testCase.expectedError != nil && !errors.Is(err, testCase.expectedError) {
+				t.Errorf("expected error %v, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestSSEClientListenTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Accept") != "text/event-stream" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: authenticated\n\n"))
+	}))
+	defer server.Close()
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caPath, ca, 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Setenv("SSE_TEST_TOKEN", "secret")
+
+	client := newTestClient(t, Config{
+		ServerURL: server.URL,
+		Transport: TransportConfig{
+			BearerTokenEnv: "SSE_TEST_TOKEN",
+			CAFile:         caPath,
+		},
+	})
+	defer client.Close()
+
+	sub, err := client.NewSubscriber(context.Background(), SubscriberConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	go func() {
+		_ = client.Listen(context.Background())
+	}()
+
+	if event := <-sub.Events(); string(event.Data) != "authenticated" {
+		t.Errorf("expected the authenticated stream, got %q", event.Data)
+	}
+}
+
+func TestNewSSEClientInvalidTransport(t *testing.T) {
+	if _, err := NewSSEClient(Config{Transport: TransportConfig{CAFile: "missing.pem"}}, loggerInstance); err == nil {
+		t.Errorf("expected an error, got nil")
+	}
+
+	if _, err := NewSSEClient(Config{Transport: TransportConfig{BearerTokenEnv: "SSE_TEST_UNSET_TOKEN"}}, loggerInstance); !errors.Is(err, ErrMissingBearerToken) {
+		t.Errorf("expected error %v, got %v", ErrMissingBearerToken, err)
+	}
+}
@@ -0,0 +1,59 @@
+package sse
+
+import (
+	"io"
+	"time"
+)
+
+// watchdog calls onIdle when it hasn't been kicked for timeout.
+// A watchdog with a zero timeout is disabled.
+type watchdog struct {
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func newWatchdog(timeout time.Duration, onIdle func()) *watchdog {
+	if timeout <= 0 {
+		return &watchdog{}
+	}
+
+	return &watchdog{
+		timer:   time.AfterFunc(timeout, onIdle),
+		timeout: timeout,
+	}
+}
+
+// Kick postpones the idle deadline.
+func (w *watchdog) Kick() {
+	if w.timer != nil {
+		w.timer.Reset(w.timeout)
+	}
+}
+
+func (w *watchdog) Stop() {
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+// Reader wraps r so that every received byte, comments included, kicks the watchdog.
+func (w *watchdog) Reader(r io.Reader) io.Reader {
+	return &watchedReader{
+		reader:   r,
+		watchdog: w,
+	}
+}
+
+type watchedReader struct {
+	reader   io.Reader
+	watchdog *watchdog
+}
+
+func (r *watchedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.watchdog.Kick()
+	}
+
+	return n, err
+}
@@ -0,0 +1,72 @@
+package sse
+
+import (
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchdogIdle(t *testing.T) {
+	var idle atomic.Bool
+
+	watchdog := newWatchdog(50*time.Millisecond, func() {
+		idle.Store(true)
+	})
+	defer watchdog.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+
+	if !idle.Load() {
+		t.Errorf("expected watchdog to fire")
+	}
+}
+
+func TestWatchdogKick(t *testing.T) {
+	var idle atomic.Bool
+
+	watchdog := newWatchdog(100*time.Millisecond, func() {
+		idle.Store(true)
+	})
+	defer watchdog.Stop()
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(50 * time.Millisecond)
+		watchdog.Kick()
+	}
+
+	if idle.Load() {
+		t.Errorf("kicked watchdog shouldn't fire")
+	}
+}
+
+func TestWatchdogReader(t *testing.T) {
+	var idle atomic.Bool
+
+	watchdog := newWatchdog(100*time.Millisecond, func() {
+		idle.Store(true)
+	})
+	defer watchdog.Stop()
+
+	for i := 0; i < 5; i++ {
+		time.Sleep(50 * time.Millisecond)
+
+		if _, err := io.ReadAll(watchdog.Reader(strings.NewReader(": heartbeat\n"))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if idle.Load() {
+		t.Errorf("watchdog shouldn't fire while bytes are read")
+	}
+}
+
+func TestWatchdogDisabled(t *testing.T) {
+	watchdog := newWatchdog(0, func() {
+		t.Errorf("disabled watchdog shouldn't fire")
+	})
+
+	watchdog.Kick()
+	watchdog.Stop()
+}
@@ -0,0 +1,53 @@
+package synthetic
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// DistributionKind is the law the values of a metric follow.
+type DistributionKind string
+
+const (
+	// Uniform draws the values uniformly between Min and Max. It is the default kind.
+	Uniform DistributionKind = "uniform"
+
+	// Exponential draws values around Min+Mean, with a long tail of popular posts capped at Max if set.
+	Exponential DistributionKind = "exponential"
+
+	// Normal draws values around Mean with the standard deviation StdDev, bounded by Min and Max if set.
+	Normal DistributionKind = "normal"
+)
+
+// Distribution configures how the values of a metric are drawn.
+type Distribution struct {
+	Kind   DistributionKind `json:"kind"`
+	Min    int64            `json:"min"`
+	Max    int64            `json:"max"`
+	Mean   float64          `json:"mean"`
+	StdDev float64          `json:"std_dev"`
+}
+
+func (d Distribution) draw(r *rand.Rand) int64 {
+	var value float64
+
+	switch d.Kind {
+	case Exponential:
+		value = float64(d.Min) + r.ExpFloat64()*d.Mean
+	case Normal:
+		value = d.Mean + r.NormFloat64()*d.StdDev
+	default:
+		if d.Max <= d.Min {
+			return d.Min
+		}
+
+		return d.Min + r.Int64N(d.Max-d.Min+1)
+	}
+
+	value = max(math.Round(value), float64(d.Min))
+	if d.Max > d.Min {
+		value = min(value, float64(d.Max))
+	}
+
+	return int64(value)
+}
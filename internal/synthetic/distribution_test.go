@@ -0,0 +1,55 @@
+package synthetic
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestDistributionDraw(t *testing.T) {
+	type testData struct {
+		name         string
+		distribution Distribution
+		expectedMin  int64
+		expectedMax  int64
+	}
+
+	testCases := [...]testData{
+		{
+			name:         "Success case: uniform",
+			distribution: Distribution{Min: 5, Max: 10},
+			expectedMin:  5,
+			expectedMax:  10,
+		},
+		{
+			name:         "Success case: constant",
+			distribution: Distribution{Kind: Uniform, Min: 3},
+			expectedMin:  3,
+			expectedMax:  3,
+		},
+		{
+			name:         "Success case: capped exponential",
+			distribution: Distribution{Kind: Exponential, Min: 1, Max: 50, Mean: 100},
+			expectedMin:  1,
+			expectedMax:  50,
+		},
+		{
+			name:         "Success case: bounded normal",
+			distribution: Distribution{Kind: Normal, Max: 20, Mean: 10, StdDev: 30},
+			expectedMin:  0,
+			expectedMax:  20,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := rand.New(rand.NewPCG(1, 1))
+
+			for range 1000 {
+				value := testCase.distribution.draw(r)
+				if value < testCase.expectedMin || value > testCase.expectedMax {
+					t.Fatalf("expected a value between %d and %d, got %d", testCase.expectedMin, testCase.expectedMax, value)
+				}
+			}
+		})
+	}
+}
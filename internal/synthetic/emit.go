@@ -0,0 +1,38 @@
+package synthetic
+
+import (
+	"context"
+	"time"
+)
+
+// tick is the shortest delay between two bursts of events.
+const tick = 10 * time.Millisecond
+
+// Emit calls emit with the number of events due at rate per second, until ctx is done or emit fails.
+// High rates are reached by emitting every event due since the previous tick at once.
+// It returns the ctx error or the emit one.
+func Emit(ctx context.Context, rate float64, emit func(now time.Time, count int) error) error {
+	interval := max(time.Duration(float64(time.Second)/rate), tick)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	emitted := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			due := int(now.Sub(start).Seconds() * rate)
+			if due == emitted {
+				continue
+			}
+
+			if err := emit(now, due-emitted); err != nil {
+				return err
+			}
+			emitted = due
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package synthetic
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEmit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	emitted := 0
+	err := Emit(ctx, 1000, func(_ time.Time, count int) error {
+		emitted += count
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	// Bursts catch up with the rate despite the ticks being longer than the events interval.
+	if emitted < 100 || emitted > 200 {
+		t.Errorf("expected about 200 events, got %d", emitted)
+	}
+
+	stop := errors.New("stop")
+	if err := Emit(context.Background(), 1000, func(time.Time, int) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("expected error %v, got %v", stop, err)
+	}
+}
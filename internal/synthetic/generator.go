@@ -0,0 +1,131 @@
+// Package synthetic generates posts shaped like the ones of the Upfluence stream.
+package synthetic
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// platformMetrics lists the metrics of the posts of each platform.
+var platformMetrics = map[string][]string{
+	"tweet":           {"retweets", "favorites", "comments"},
+	"instagram_media": {"likes", "comments"},
+	"youtube_video":   {"likes", "comments", "views"},
+	"pin":             {"likes", "comments", "repins"},
+	"facebook_status": {"likes", "comments", "shares"},
+	"tiktok_video":    {"likes", "comments", "shares", "plays"},
+	"article":         {"comments"},
+}
+
+// Platforms returns the platforms the generator knows about, sorted by name.
+func Platforms() []string {
+	platforms := make([]string, 0, len(platformMetrics))
+	for platform := range platformMetrics {
+		platforms = append(platforms, platform)
+	}
+	slices.Sort(platforms)
+
+	return platforms
+}
+
+type Config struct {
+	// Platforms of the generated posts, all the known platforms by default.
+	Platforms []string `json:"platforms"`
+
+	// MaxMetric is the upper bound of the metrics without distribution, defaults to 1000.
+	MaxMetric int64 `json:"max_metric"`
+
+	// Distributions sets how the values of a metric are drawn, by metric name.
+	// Metrics without distribution are uniformly drawn between 0 and MaxMetric.
+	Distributions map[string]Distribution `json:"distributions"`
+
+	// MissingRate is the probability, between 0 and 1, of a metric to be left out of a post.
+	MissingRate float64 `json:"missing_rate"`
+
+	// MalformedRate is the probability, between 0 and 1, of a payload not to be a valid post.
+	MalformedRate float64 `json:"malformed_rate"`
+
+	// Seed makes the generated posts reproducible. A random seed is used if 0.
+	Seed uint64 `json:"seed"`
+}
+
+// Generator generates event payloads made of a single post keyed by its platform.
+// It is not safe for concurrent use.
+type Generator struct {
+	config    Config
+	platforms []string
+	rand      *rand.Rand
+	nextID    int64
+}
+
+func NewGenerator(config Config) *Generator {
+	platforms := config.Platforms
+	if len(platforms) == 0 {
+		platforms = Platforms()
+	}
+
+	if config.MaxMetric <= 0 {
+		config.MaxMetric = 1000
+	}
+
+	seed := config.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+
+	return &Generator{
+		config:    config,
+		platforms: platforms,
+		rand:      rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+// Post returns the platform and the fields of a new post published at now.
+func (g *Generator) Post(now time.Time) (string, map[string]any) {
+	platform := g.platforms[g.rand.IntN(len(g.platforms))]
+
+	g.nextID++
+	post := map[string]any{
+		"id":        g.nextID,
+		"timestamp": now.Unix(),
+	}
+
+	for _, metric := range platformMetrics[platform] {
+		if g.rand.Float64() < g.config.MissingRate {
+			continue
+		}
+
+		distribution, ok := g.config.Distributions[metric]
+		if !ok {
+			distribution = Distribution{Max: g.config.MaxMetric}
+		}
+
+		post[metric] = distribution.draw(g.rand)
+	}
+
+	return platform, post
+}
+
+// Next returns the payload of a new post published at now, or a malformed payload as often as configured.
+func (g *Generator) Next(now time.Time) []byte {
+	platform, post := g.Post(now)
+
+	payload, _ := json.Marshal(map[string]any{platform: post})
+
+	if g.rand.Float64() < g.config.MalformedRate {
+		return g.malformed(payload)
+	}
+
+	return payload
+}
+
+// malformed damages a payload: it is either truncated or replaced by plain text.
+func (g *Generator) malformed(payload []byte) []byte {
+	if g.rand.IntN(2) == 0 {
+		return payload[:len(payload)/2]
+	}
+
+	return []byte("this is not a post")
+}
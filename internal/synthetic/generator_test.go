@@ -0,0 +1,101 @@
+package synthetic
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGeneratorNext(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	generator := NewGenerator(Config{Platforms: []string{"tweet", "pin"}, MaxMetric: 10, Seed: 1})
+
+	for range 100 {
+		payload := map[string]map[string]int64{}
+		if err := json.Unmarshal(generator.Next(now), &payload); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(payload) != 1 {
+			t.Fatalf("expected a single post, got %v", payload)
+		}
+
+		for platform, post := range payload {
+			if platform != "tweet" && platform != "pin" {
+				t.Errorf("unexpected platform %s", platform)
+			}
+
+			if post["timestamp"] != now.Unix() {
+				t.Errorf("expected timestamp %d, got %d", now.Unix(), post["timestamp"])
+			}
+
+			for _, metric := range platformMetrics[platform] {
+				if value, ok := post[metric]; !ok || value < 0 || value > 10 {
+					t.Errorf("unexpected %s value %d", metric, value)
+				}
+			}
+		}
+	}
+}
+
+func TestGeneratorNextDegraded(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	generator := NewGenerator(Config{
+		Platforms:     []string{"instagram_media"},
+		Distributions: map[string]Distribution{"likes": {Min: 7, Max: 7}},
+		MissingRate:   0.5,
+		MalformedRate: 0.5,
+		Seed:          1,
+	})
+
+	malformed, missing := 0, 0
+	for range 1000 {
+		payload := map[string]map[string]int64{}
+		if err := json.Unmarshal(generator.Next(now), &payload); err != nil {
+			malformed++
+			continue
+		}
+
+		post := payload["instagram_media"]
+		likes, ok := post["likes"]
+		if !ok {
+			missing++
+		} else if likes != 7 {
+			t.Errorf("expected likes to follow their distribution, got %d", likes)
+		}
+	}
+
+	if malformed < 400 || malformed > 600 {
+		t.Errorf("expected about half of the payloads to be malformed, got %d", malformed)
+	}
+
+	if missing < 200 || missing > 300 {
+		t.Errorf("expected about half of the valid posts to miss likes, got %d", missing)
+	}
+}
+
+func TestGeneratorSeed(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	a := NewGenerator(Config{Seed: 42})
+	b := NewGenerator(Config{Seed: 42})
+
+	for range 10 {
+		if !bytes.Equal(a.Next(now), b.Next(now)) {
+			t.Fatalf("expected generators with the same seed to generate the same posts")
+		}
+	}
+}
+
+func TestPlatforms(t *testing.T) {
+	platforms := Platforms()
+
+	expected := []string{"article", "facebook_status", "instagram_media", "pin", "tiktok_video", "tweet", "youtube_video"}
+	if !slices.Equal(platforms, expected) {
+		t.Errorf("expected %v, got %v", expected, platforms)
+	}
+}
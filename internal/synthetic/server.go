@@ -0,0 +1,83 @@
+package synthetic
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultRatePerSecond = 10
+
+// errDisconnect ends a connection once it sent the configured number of events.
+var errDisconnect = errors.New("disconnect")
+
+// ServerConfig configures a fake stream endpoint.
+type ServerConfig struct {
+	Config
+
+	// RatePerSecond is the number of events sent per second on each connection, defaults to 10.
+	RatePerSecond float64 `json:"rate_per_second"`
+
+	// DisconnectAfter is the number of events after which a connection is closed by the server.
+	// Leave it to 0 to keep the connections open.
+	DisconnectAfter int `json:"disconnect_after"`
+}
+
+func (c ServerConfig) rate() float64 {
+	if c.RatePerSecond <= 0 {
+		return defaultRatePerSecond
+	}
+
+	return c.RatePerSecond
+}
+
+// Handler serves a stream of generated posts compatible with the Upfluence one. Events are numbered,
+// a client reconnecting with a Last-Event-ID header resumes the numbering after it.
+type Handler struct {
+	config ServerConfig
+}
+
+func NewHandler(config ServerConfig) *Handler {
+	return &Handler{
+		config: config,
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	id, _ := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	generator := NewGenerator(h.config.Config)
+	sent := 0
+
+	// The connection ends when the client leaves, on a write error, or once enough events were sent.
+	_ = Emit(r.Context(), h.config.rate(), func(now time.Time, count int) error {
+		defer flusher.Flush()
+
+		for range count {
+			id++
+			if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", id, generator.Next(now)); err != nil {
+				return err
+			}
+
+			sent++
+			if h.config.DisconnectAfter > 0 && sent >= h.config.DisconnectAfter {
+				return errDisconnect
+			}
+		}
+
+		return nil
+	})
+}
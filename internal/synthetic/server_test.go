@@ -0,0 +1,49 @@
+package synthetic
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(NewHandler(ServerConfig{
+		Config:          Config{Platforms: []string{"tweet"}},
+		RatePerSecond:   1000,
+		DisconnectAfter: 5,
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req.Header.Set("Last-Event-ID", "41")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+
+	if contentType := res.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("expected an event stream, got %s", contentType)
+	}
+
+	// The server disconnects after 5 events, so the body ends.
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := strings.Split(strings.TrimSpace(string(body)), "\n\n")
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(events))
+	}
+
+	if !strings.HasPrefix(events[0], "id: 42\ndata: {\"tweet\":") {
+		t.Errorf("expected the numbering to resume after the last event ID, got %q", events[0])
+	}
+}
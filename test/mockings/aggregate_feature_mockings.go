@@ -1,8 +1,9 @@
 package mockings
 
 import (
+	"context"
 	"errors"
-	"time"
+	"fmt"
 
 	"github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/features/aggregate"
 )
@@ -11,21 +12,44 @@ var ErrInvalidData = errors.New("invalid data")
 
 type AggregateFeatureMocking struct{}
 
-func (a *AggregateFeatureMocking) Aggregate(_ time.Duration, _ string) (*aggregate.PostsStatAggregation, error) {
+func (a *AggregateFeatureMocking) Aggregate(_ context.Context, _ aggregate.Query) (*aggregate.PostsStatAggregation, error) {
 	return &aggregate.PostsStatAggregation{
-		TotalPosts:       12,
-		MinimumTimestamp: 1,
-		MaximumTimestamp: 3,
-		AvgLikes:         intP(2),
+		PostsStats: aggregate.PostsStats{
+			TotalPosts:       12,
+			MinimumTimestamp: 1,
+			MaximumTimestamp: 3,
+			Averages:         map[string]float64{"likes": 2},
+		},
 	}, nil
 }
 
+// AggregateFeatureQueryMocking records the query of the last aggregation.
+type AggregateFeatureQueryMocking struct {
+	AggregateFeatureMocking
+
+	Query aggregate.Query
+}
+
+func (a *AggregateFeatureQueryMocking) Aggregate(ctx context.Context, query aggregate.Query) (*aggregate.PostsStatAggregation, error) {
+	a.Query = query
+
+	return a.AggregateFeatureMocking.Aggregate(ctx, query)
+}
+
 type AggregateFeatureErrorMocking struct{}
 
-func (a *AggregateFeatureErrorMocking) Aggregate(_ time.Duration, _ string) (*aggregate.PostsStatAggregation, error) {
+func (a *AggregateFeatureErrorMocking) Aggregate(_ context.Context, _ aggregate.Query) (*aggregate.PostsStatAggregation, error) {
 	return nil, ErrInvalidData
 }
 
-func intP(i int) *int {
-	return &i
+type AggregateFeatureUnavailableMocking struct{}
+
+func (a *AggregateFeatureUnavailableMocking) Aggregate(_ context.Context, _ aggregate.Query) (*aggregate.PostsStatAggregation, error) {
+	return nil, fmt.Errorf("can't read aggregate: %w", aggregate.ErrStreamUnavailable)
+}
+
+type AggregateFeatureLookbackUnavailableMocking struct{}
+
+func (a *AggregateFeatureLookbackUnavailableMocking) Aggregate(_ context.Context, _ aggregate.Query) (*aggregate.PostsStatAggregation, error) {
+	return nil, fmt.Errorf("can't read aggregate: %w", aggregate.ErrLookbackUnavailable)
 }
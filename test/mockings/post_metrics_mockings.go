@@ -0,0 +1,13 @@
+package mockings
+
+import "github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/posts"
+
+type PostMetricsMocking struct{}
+
+func (m *PostMetricsMocking) Stats() posts.Stats {
+	return posts.Stats{
+		DecodedEvents: 10,
+		DecodeErrors:  2,
+		SkippedEvents: 1,
+	}
+}
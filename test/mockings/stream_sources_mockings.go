@@ -0,0 +1,9 @@
+package mockings
+
+type StreamSourcesMocking struct {
+	Names []string
+}
+
+func (s *StreamSourcesMocking) Sources() []string {
+	return s.Names
+}
@@ -0,0 +1,18 @@
+package mockings
+
+import "github.com/FloRichardAloeCorp/upfluence-coding-challenge/internal/interfaces/sse"
+
+type StreamStatusMocking struct {
+	StreamState sse.State
+}
+
+func (s *StreamStatusMocking) State() sse.State {
+	return s.StreamState
+}
+
+func (s *StreamStatusMocking) Stats() sse.Stats {
+	return sse.Stats{
+		Reconnections: 2,
+		Resumes:       1,
+	}
+}